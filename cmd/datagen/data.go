@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// gameMarker ends the positions of each game, so interrupted runs can resume.
+// It's a comment to tune.
+const gameMarker = "# game "
+
+// sample is a position and its search score, in centipawns from the point of
+// view of the side to move.
+type sample struct {
+	fen   string
+	score int
+}
+
+// game holds the samples of a self-play game and its result.
+type game struct {
+	index   int
+	samples []sample
+	result  string // as in PGN
+}
+
+// Write writes the samples of the game as EPD, one per line with the score in
+// a "ce" opcode and the result in a "c9" opcode, followed by the game marker.
+// A game is written at once, so it's either complete or the last in the file.
+func (game *game) Write(w io.Writer) error {
+	var builder strings.Builder
+	for _, s := range game.samples {
+		fmt.Fprintf(&builder, "%s ce %d; c9 \"%s\";\n", s.fen, s.score, game.result)
+	}
+	fmt.Fprintf(&builder, "%s%d\n", gameMarker, game.index)
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// pending returns the indices of the games of a run of games that aren't
+// done, in order, on a channel closed after the last.
+func pending(games int, done map[int]bool) <-chan int {
+	indices := make(chan int)
+	go func() {
+		for i := 0; i < games; i++ {
+			if !done[i] {
+				indices <- i
+			}
+		}
+		close(indices)
+	}()
+	return indices
+}
+
+// resume returns the indices of the games in the file at path, truncating any
+// incomplete game at its end.  A missing file has no games.
+func resume(path string) (map[int]bool, error) {
+	done := make(map[int]bool)
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return done, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var offset, end int64
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		offset += int64(len(line))
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		if strings.HasPrefix(line, gameMarker) {
+			index, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, gameMarker)))
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("invalid game marker %q", strings.TrimSpace(line))
+			}
+			done[index] = true
+			end = offset
+		}
+	}
+	f.Close()
+
+	if end < offset {
+		if err := os.Truncate(path, end); err != nil {
+			return nil, err
+		}
+	}
+	return done, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Data", func() {
+	var (
+		dir  string
+		path string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "datagen")
+		Expect(err).
+			ToNot(HaveOccurred())
+		path = filepath.Join(dir, "data.epd")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(games ...*game) string {
+		var builder strings.Builder
+		for _, g := range games {
+			Expect(g.Write(&builder)).
+				To(Succeed())
+		}
+		return builder.String()
+	}
+
+	first := &game{0, []sample{{"8/8/8/8/8/8/4K3/4k3 w - - 0 1", 15}}, "1/2-1/2"}
+	second := &game{3, []sample{
+		{"4k3/8/8/8/8/8/4P3/4K3 w - - 0 1", 120},
+		{"4k3/8/8/8/8/4P3/8/4K3 b - - 0 1", -110},
+	}, "1-0"}
+
+	It("Writes each position with its score and the game result", func() {
+		Expect(write(second)).
+			To(Equal("4k3/8/8/8/8/8/4P3/4K3 w - - 0 1 ce 120; c9 \"1-0\";\n" +
+				"4k3/8/8/8/8/4P3/8/4K3 b - - 0 1 ce -110; c9 \"1-0\";\n" +
+				"# game 3\n"))
+	})
+
+	It("Resumes nothing without a file", func() {
+		done, err := resume(path)
+		Expect(err).
+			ToNot(HaveOccurred())
+		Expect(done).
+			To(BeEmpty())
+	})
+
+	It("Resumes after the complete games", func() {
+		complete := write(first, second)
+		Expect(ioutil.WriteFile(path, []byte(complete), 0644)).
+			To(Succeed())
+
+		done, err := resume(path)
+		Expect(err).
+			ToNot(HaveOccurred())
+		Expect(done).
+			To(Equal(map[int]bool{0: true, 3: true}))
+		Expect(ioutil.ReadFile(path)).
+			To(BeEquivalentTo(complete))
+	})
+
+	It("Truncates a partial game at the end", func() {
+		complete := write(first)
+		partial := "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1 ce 120; c9 \"1-0\";\n4k3/8/8/8/8/4P3"
+		Expect(ioutil.WriteFile(path, []byte(complete+partial), 0644)).
+			To(Succeed())
+
+		done, err := resume(path)
+		Expect(err).
+			ToNot(HaveOccurred())
+		Expect(done).
+			To(Equal(map[int]bool{0: true}))
+		Expect(ioutil.ReadFile(path)).
+			To(BeEquivalentTo(complete))
+	})
+
+	It("Rejects an invalid game marker", func() {
+		Expect(ioutil.WriteFile(path, []byte(gameMarker+"x\n"), 0644)).
+			To(Succeed())
+
+		_, err := resume(path)
+		Expect(err).
+			To(HaveOccurred())
+	})
+
+	It("Skips the games already done", func() {
+		var indices []int
+		for i := range pending(6, map[int]bool{0: true, 3: true, 4: true, 9: true}) {
+			indices = append(indices, i)
+		}
+		Expect(indices).
+			To(Equal([]int{1, 2, 5}))
+	})
+})
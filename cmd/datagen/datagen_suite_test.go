@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDatagen(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Datagen Suite")
+}
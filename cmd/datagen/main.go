@@ -0,0 +1,84 @@
+// Command datagen plays self-play games with the minimax engine from
+// randomised openings, and writes the quiet positions of each game with their
+// search scores and the game result, as training data for tune or networks.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"runtime"
+	"sync"
+)
+
+func main() {
+	var (
+		out         = flag.String("out", "data.epd", "append the positions to `file`, resuming the games in it")
+		games       = flag.Int("games", 1000, "play this many games in total")
+		nodes       = flag.Int("nodes", 5000, "search this many nodes per move")
+		depth       = flag.Int("depth", 0, "search to this depth per move, instead of a number of nodes")
+		threads     = flag.Int("threads", runtime.NumCPU(), "play this many games in parallel")
+		randomPlies = flag.Int("random-plies", 8, "start games with this many random plies")
+		maxPlies    = flag.Int("max-plies", 400, "adjudicate games as draws after this many plies")
+		hash        = flag.Int("hash", 16, "transposition table size per game in MB")
+		seed        = flag.Int64("seed", 1, "seed the random openings")
+	)
+	flag.Parse()
+
+	if *games <= 0 || *threads <= 0 || (*nodes <= 0 && *depth <= 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	done, err := resume(*out)
+	if err != nil {
+		log.Fatalf("Error resuming %s: %s", *out, err)
+	}
+	if len(done) > 0 {
+		log.Printf("Resuming after %d games", len(done))
+	}
+
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		log.Fatalf("Error opening %s: %s", *out, err)
+	}
+	defer f.Close()
+
+	config := config{
+		nodes:       *nodes,
+		depth:       *depth,
+		randomPlies: *randomPlies,
+		maxPlies:    *maxPlies,
+		hash:        *hash,
+		seed:        *seed}
+
+	indices := pending(*games, done)
+
+	results := make(chan *game)
+	var workers sync.WaitGroup
+	for i := 0; i < *threads; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			player := newPlayer(config)
+			for index := range indices {
+				results <- player.Play(index)
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	played, positions := len(done), 0
+	for game := range results {
+		if err := game.Write(f); err != nil {
+			log.Fatalf("Error writing %s: %s", *out, err)
+		}
+		played++
+		positions += len(game.samples)
+		log.Printf("Game %d: %s, %d positions (%d/%d games, %d positions)",
+			game.index, game.result, len(game.samples), played, *games, positions)
+	}
+}
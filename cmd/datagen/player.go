@@ -0,0 +1,156 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+
+	"github.com/notnil/chess"
+
+	"github.com/mhv2109/uci-impl/internal/handler"
+	"github.com/mhv2109/uci-impl/internal/handler/info"
+	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/minimax"
+)
+
+// config holds the settings of the self-play games.
+type config struct {
+	nodes       int
+	depth       int
+	randomPlies int
+	maxPlies    int
+	hash        int
+	seed        int64
+}
+
+// scoreEmitter records the score of the last completed search iteration,
+// instead of printing it.
+type scoreEmitter struct {
+	handler.Emitter
+
+	mutex sync.Mutex
+	score int
+	mate  bool
+}
+
+func (emitter *scoreEmitter) EmitInfo(i info.Info) {
+	scoretype, value, ok := i.Score()
+	if !ok || i.ScoreBound() != info.Exact || len(i.Pv()) == 0 {
+		return
+	}
+
+	emitter.mutex.Lock()
+	defer emitter.mutex.Unlock()
+
+	emitter.score = value
+	emitter.mate = scoretype == info.Mate
+}
+
+func (emitter *scoreEmitter) Score() (int, bool) {
+	emitter.mutex.Lock()
+	defer emitter.mutex.Unlock()
+
+	return emitter.score, emitter.mate
+}
+
+// player plays self-play games with an engine of its own.
+type player struct {
+	config  config
+	emitter *scoreEmitter
+	engine  solver.Solver
+}
+
+func newPlayer(config config) *player {
+	emitter := &scoreEmitter{Emitter: handler.NewEmitter()}
+	engine := minimax.NewMinimaxSolverWithEmitter(emitter)
+	engine.SetOption("Hash", strconv.Itoa(config.hash))
+
+	return &player{config, emitter, engine}
+}
+
+// Play plays the game with index, from an opening chosen by the index, so
+// resumed runs play the same games.
+func (player *player) Play(index int) *game {
+	rng := rand.New(rand.NewSource(player.config.seed + int64(index)))
+	g := player.opening(rng)
+	result := &game{index: index}
+
+	player.engine.NewGame()
+	for g.Outcome() == chess.NoOutcome {
+		if len(g.Moves()) >= player.config.maxPlies || claimableDraw(g) {
+			g.Draw(chess.DrawOffer)
+			break
+		}
+
+		moves := make([]string, len(g.Moves()))
+		for i, move := range g.Moves() {
+			moves[i] = move.String()
+		}
+		player.engine.SetStartPosition(moves...)
+
+		sp := solver.NewSearchParams()
+		if player.config.depth > 0 {
+			sp.Depth = player.config.depth
+		} else {
+			sp.Nodes = player.config.nodes
+		}
+		var best []string
+		for best = range player.engine.StartSearch(sp) {
+		}
+
+		move, err := chess.LongAlgebraicNotation{}.Decode(g.Position(), best[0])
+		if err != nil {
+			panic(err)
+		}
+
+		if score, mate := player.emitter.Score(); !mate && quiet(g, move) {
+			result.samples = append(result.samples, sample{g.Position().String(), score})
+		}
+
+		if err := g.Move(move); err != nil {
+			panic(err)
+		}
+	}
+
+	result.result = g.Outcome().String()
+	if g.Outcome() == chess.NoOutcome {
+		result.result = chess.Draw.String()
+	}
+	return result
+}
+
+// opening returns a game started with random moves, that hasn't ended.
+func (player *player) opening(rng *rand.Rand) *chess.Game {
+	for {
+		g := chess.NewGame(chess.UseNotation(chess.LongAlgebraicNotation{}))
+		for i := 0; i < player.config.randomPlies && g.Outcome() == chess.NoOutcome; i++ {
+			moves := g.ValidMoves()
+			g.Move(moves[rng.Intn(len(moves))])
+		}
+		if g.Outcome() == chess.NoOutcome {
+			return g
+		}
+	}
+}
+
+// quiet returns true if the side to move isn't in check and move isn't a
+// capture or promotion, so the position's evaluation should match its score.
+func quiet(g *chess.Game, move *chess.Move) bool {
+	if moves := g.Moves(); len(moves) > 0 && moves[len(moves)-1].HasTag(chess.Check) {
+		return false
+	}
+	return !move.HasTag(chess.Capture) && !move.HasTag(chess.EnPassant) &&
+		move.Promo() == chess.NoPieceType
+}
+
+// claimableDraw returns true if either side could claim a draw by threefold
+// repetition or the fifty-move rule, which the engine plays for if it's
+// worse.
+func claimableDraw(g *chess.Game) bool {
+	for _, method := range g.EligibleDraws() {
+		if method == chess.ThreefoldRepetition || method == chess.FiftyMoveRule {
+			return true
+		}
+	}
+	return false
+}
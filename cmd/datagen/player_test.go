@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/notnil/chess"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+var _ = Describe("Player", func() {
+	// after returns the game from fen with moves made, and the move that
+	// follows them.
+	after := func(fen string, moves ...string) (*chess.Game, *chess.Move) {
+		f, err := chess.FEN(fen)
+		Expect(err).
+			ToNot(HaveOccurred())
+		g := chess.NewGame(f, chess.UseNotation(chess.LongAlgebraicNotation{}))
+		for _, m := range moves[:len(moves)-1] {
+			Expect(g.MoveStr(m)).
+				To(Succeed())
+		}
+		move, err := chess.LongAlgebraicNotation{}.Decode(g.Position(), moves[len(moves)-1])
+		Expect(err).
+			ToNot(HaveOccurred())
+		return g, move
+	}
+
+	It("Finds quiet positions", func() {
+		Expect(quiet(after(board.StartFEN, "e2e4"))).
+			To(BeTrue())
+		Expect(quiet(after("4k3/8/8/3p4/4P3/8/8/4K3 w - - 0 1", "e4d5"))).
+			To(BeFalse())
+		Expect(quiet(after("4k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a7a8q"))).
+			To(BeFalse())
+		Expect(quiet(after("4k3/8/8/8/8/8/8/R3K3 w - - 0 1", "a1a8", "e8d7"))).
+			To(BeFalse())
+	})
+
+	It("Writes only quiet positions, labelled with the result", func() {
+		player := newPlayer(config{depth: 1, randomPlies: 4, maxPlies: 30, hash: 1, seed: 1})
+		g := player.Play(0)
+		Expect(g.index).
+			To(Equal(0))
+		Expect(g.samples).
+			ToNot(BeEmpty())
+		Expect([]string{"1-0", "0-1", "1/2-1/2"}).
+			To(ContainElement(g.result))
+
+		for _, s := range g.samples {
+			position, err := board.ParseFEN(s.fen)
+			Expect(err).
+				ToNot(HaveOccurred())
+			Expect(position.InCheck()).
+				To(BeFalse(), s.fen)
+		}
+
+		var builder strings.Builder
+		Expect(g.Write(&builder)).
+			To(Succeed())
+		lines := strings.Split(strings.TrimSuffix(builder.String(), "\n"), "\n")
+		Expect(lines).
+			To(HaveLen(len(g.samples) + 1))
+		for _, line := range lines[:len(g.samples)] {
+			Expect(line).
+				To(HaveSuffix("; c9 \"%s\";", g.result))
+		}
+	})
+
+	It("Plays the same game from the same index", func() {
+		cfg := config{depth: 1, randomPlies: 6, maxPlies: 20, hash: 1, seed: 7}
+		Expect(newPlayer(cfg).Play(2)).
+			To(Equal(newPlayer(cfg).Play(2)))
+	})
+})
@@ -1,13 +1,43 @@
 package main
 
 import (
+	"flag"
+	"log"
+	"os"
+
 	"github.com/mhv2109/uci-impl/internal/handler"
 	"github.com/mhv2109/uci-impl/internal/solver/minimax"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
 )
 
 // main program
 func main() {
+	params := flag.String("params", "", "load evaluation parameters from `file`, as written by tune")
+	bench := flag.Bool("bench", false, "search a fixed set of positions, print the nodes searched and exit")
+	flag.Parse()
+
+	if *params != "" {
+		loadParams(*params)
+	}
+
 	solver := minimax.NewMinimaxSolver()
+	if *bench {
+		handler.NewHandler(solver).Handle([]string{"bench"})
+		return
+	}
+
 	server := handler.NewServer(solver)
 	server.ServeForever()
 }
+
+func loadParams(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Error opening parameter file: %s", err)
+	}
+	defer f.Close()
+
+	if err := utils.LoadParams(f); err != nil {
+		log.Fatalf("Error loading parameter file %s: %s", path, err)
+	}
+}
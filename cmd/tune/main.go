@@ -0,0 +1,100 @@
+// Command tune fits the evaluation parameters of the engines to a set of
+// positions labelled with game results, Texel style, and writes them to a file
+// the minimax engine loads with its -params flag.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+func main() {
+	var (
+		data   = flag.String("data", "", "read labelled positions from `file` (required)")
+		out    = flag.String("out", "params.txt", "write the tuned parameters to `file`")
+		params = flag.String("params", "", "start from the parameters in `file`")
+		k      = flag.Float64("k", 0, "sigmoid scaling; fitted to the data if 0")
+		passes = flag.Int("passes", 100, "stop after this many passes over the parameters")
+		eval   = flag.String("evaluator", solver.DefaultEvaluatorName, "tune the parameters of the `evaluator`")
+	)
+	flag.Parse()
+
+	if *data == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *params != "" {
+		f, err := os.Open(*params)
+		if err != nil {
+			log.Fatalf("Error opening parameter file: %s", err)
+		}
+		err = utils.LoadParams(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("Error loading parameter file %s: %s", *params, err)
+		}
+	}
+
+	f, err := os.Open(*data)
+	if err != nil {
+		log.Fatalf("Error opening data file: %s", err)
+	}
+	positions, err := readPositions(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("Error reading data file %s: %s", *data, err)
+	}
+	if len(positions) == 0 {
+		log.Fatalf("No positions in %s", *data)
+	}
+	log.Printf("Read %d positions", len(positions))
+
+	tuner := newTuner(positions, *eval)
+	if *k > 0 {
+		tuner.k = *k
+	} else {
+		log.Printf("Fitted K = %.3f", tuner.FitK())
+	}
+
+	best := tuner.Error()
+	log.Printf("Initial error %.6f", best)
+
+	step := 8
+	for pass := 1; pass <= *passes; pass++ {
+		var improved bool
+		best, improved = tuner.Pass(best, step)
+		log.Printf("Pass %d, step %d: error %.6f", pass, step, best)
+
+		// save progress, as tuning takes a while
+		if err := writeParams(*out); err != nil {
+			log.Fatalf("Error writing parameter file %s: %s", *out, err)
+		}
+
+		if !improved {
+			if step == 1 {
+				break
+			}
+			step /= 2
+		}
+	}
+
+	fmt.Printf("Wrote %s, error %.6f\n", *out, best)
+}
+
+func writeParams(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := utils.WriteParams(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
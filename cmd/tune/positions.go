@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+// position is a chess position labelled with the result of the game it was
+// played in, 1 for a White win, 0.5 for a draw and 0 for a Black win.
+type position struct {
+	position *board.Position
+	result   float64
+}
+
+var (
+	pgnResult     = regexp.MustCompile(`"?(1-0|0-1|1/2-1/2)"?`)
+	numericResult = regexp.MustCompile(`^\[?(0|1|0?\.5|0\.0|1\.0)\]?;?$`)
+)
+
+// readPositions reads labelled positions, one per line.  Each line holds a FEN,
+// possibly without the move counters, and a result, either as in PGN ("1-0",
+// "1/2-1/2", "0-1", e.g. in an EPD c9 opcode) or as a number ("1.0", "[0.5]",
+// "0").  Blank lines and lines starting with "#" are skipped.
+func readPositions(r io.Reader) ([]position, error) {
+	var positions []position
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		p, err := parsePosition(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		positions = append(positions, p)
+	}
+
+	return positions, scanner.Err()
+}
+
+func parsePosition(text string) (position, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 5 {
+		return position{}, fmt.Errorf("expected a FEN and a result: %q", text)
+	}
+
+	// board, side to move, castling rights and en passant square, followed
+	// by the optional move counters
+	fen := append([]string{}, fields[:4]...)
+	rest := fields[4:]
+	for len(fen) < 6 && len(rest) > 0 {
+		if _, err := strconv.Atoi(rest[0]); err != nil {
+			break
+		}
+		fen, rest = append(fen, rest[0]), rest[1:]
+	}
+	if len(fen) == 4 {
+		fen = append(fen, "0", "1")
+	}
+
+	result, err := parseResult(strings.Join(rest, " "))
+	if err != nil {
+		return position{}, err
+	}
+
+	pos, err := board.ParseFEN(strings.Join(fen, " "))
+	if err != nil {
+		return position{}, err
+	}
+	return position{pos, result}, nil
+}
+
+func parseResult(text string) (float64, error) {
+	if m := pgnResult.FindStringSubmatch(text); m != nil {
+		switch m[1] {
+		case "1-0":
+			return 1, nil
+		case "0-1":
+			return 0, nil
+		}
+		return 0.5, nil
+	}
+
+	if m := numericResult.FindStringSubmatch(strings.TrimSpace(text)); m != nil {
+		return strconv.ParseFloat(m[1], 64)
+	}
+	return 0, fmt.Errorf("no result in %q", text)
+}
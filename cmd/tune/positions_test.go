@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+var _ = Describe("Positions", func() {
+	const start = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -"
+
+	It("Reads EPD with the result in a c9 opcode", func() {
+		p, err := parsePosition(start + ` ce 20; c9 "1-0";`)
+		Expect(err).
+			ToNot(HaveOccurred())
+		Expect(p.result).
+			To(Equal(1.0))
+		Expect(p.position.Key()).
+			To(Equal(board.NewPosition().Key()))
+	})
+
+	It("Reads a FEN followed by a result as in PGN", func() {
+		for text, result := range map[string]float64{
+			start + " 0 1 1-0":     1,
+			start + " 0 1 1/2-1/2": 0.5,
+			start + " 0-1":         0,
+		} {
+			p, err := parsePosition(text)
+			Expect(err).
+				ToNot(HaveOccurred(), text)
+			Expect(p.result).
+				To(Equal(result), text)
+		}
+	})
+
+	It("Reads numeric results", func() {
+		for text, result := range map[string]float64{
+			"1":     1,
+			"1.0":   1,
+			"[0.5]": 0.5,
+			".5":    0.5,
+			"0.0":   0,
+			"0;":    0,
+		} {
+			p, err := parsePosition(start + " 0 1 " + text)
+			Expect(err).
+				ToNot(HaveOccurred(), text)
+			Expect(p.result).
+				To(Equal(result), text)
+		}
+	})
+
+	It("Keeps the move counters of the FEN", func() {
+		p, err := parsePosition("4k3/8/8/8/8/8/4P3/4K3 b - - 12 40 0.5")
+		Expect(err).
+			ToNot(HaveOccurred())
+		Expect(p.position.HalfMoveClock()).
+			To(Equal(12))
+		Expect(p.result).
+			To(Equal(0.5))
+	})
+
+	It("Rejects malformed lines", func() {
+		for _, text := range []string{
+			start,
+			start + " 0 1",
+			start + " 0 1 2",
+			start + " 0 1 win",
+			"rnbqkbnr/pppppppp/8/8 w KQkq - 0 1 1-0",
+		} {
+			_, err := parsePosition(text)
+			Expect(err).
+				To(HaveOccurred(), text)
+		}
+	})
+
+	It("Skips blank lines and comments, and reports the line of an error", func() {
+		positions, err := readPositions(strings.NewReader(
+			"# labelled positions\n\n" + start + " 0 1 1-0\n" + start + " 0 1 0-1\n"))
+		Expect(err).
+			ToNot(HaveOccurred())
+		Expect(positions).
+			To(HaveLen(2))
+
+		_, err = readPositions(strings.NewReader(start + " 1-0\n\n" + start + "\n"))
+		Expect(err).
+			To(MatchError(HavePrefix("line 3: ")))
+	})
+})
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+// tuner fits the evaluation parameters to labelled positions by minimizing the
+// mean squared error between the game results and the evaluations mapped to
+// expected results by a sigmoid.
+type tuner struct {
+	positions  []position
+	evaluators []solver.Evaluator // one per worker, as they aren't thread safe
+	k          float64            // scales evaluations in the sigmoid
+}
+
+func newTuner(positions []position, evaluator string) *tuner {
+	evaluators := make([]solver.Evaluator, runtime.GOMAXPROCS(0))
+	for i := range evaluators {
+		evaluators[i] = solver.NewEvaluator(evaluator)
+	}
+	return &tuner{positions, evaluators, 1}
+}
+
+// sigmoid maps score, from White's point of view, to White's expected result.
+func (tuner *tuner) sigmoid(score utils.CentiPawns) float64 {
+	return 1 / (1 + math.Pow(10, -tuner.k*float64(score)/400))
+}
+
+// Error returns the mean squared error of the current parameters.
+func (tuner *tuner) Error() float64 {
+	n := len(tuner.evaluators)
+	sums := make([]float64, n)
+
+	var wg sync.WaitGroup
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(tuner.positions); i += n {
+				p := tuner.positions[i]
+				diff := p.result - tuner.sigmoid(tuner.evaluators[w].Evaluate(p.position))
+				sums[w] += diff * diff
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	var sum float64
+	for _, s := range sums {
+		sum += s
+	}
+	return sum / float64(len(tuner.positions))
+}
+
+// FitK finds the sigmoid scaling that minimizes the error of the current
+// parameters, by golden section search, so the parameters are tuned to the
+// scale of the evaluation rather than the evaluation to the sigmoid.
+func (tuner *tuner) FitK() float64 {
+	const ratio = 0.6180339887498949
+	lo, hi := 0.0, 4.0
+	errorAt := func(k float64) float64 {
+		tuner.k = k
+		return tuner.Error()
+	}
+
+	a, b := hi-ratio*(hi-lo), lo+ratio*(hi-lo)
+	ea, eb := errorAt(a), errorAt(b)
+	for hi-lo > 0.001 {
+		if ea < eb {
+			hi, b, eb = b, a, ea
+			a = hi - ratio*(hi-lo)
+			ea = errorAt(a)
+		} else {
+			lo, a, ea = a, b, eb
+			b = lo + ratio*(hi-lo)
+			eb = errorAt(b)
+		}
+	}
+
+	tuner.k = (lo + hi) / 2
+	return tuner.k
+}
+
+// Pass tries to improve each parameter by step in either direction, keeping
+// changes that reduce the error, and returns the new error and whether any
+// parameter changed.  best is the error of the current parameters.
+func (tuner *tuner) Pass(best float64, step int) (float64, bool) {
+	improved := false
+	for _, param := range utils.Params() {
+		value := param.Value()
+		for _, delta := range []int{step, -step} {
+			param.Set(value + delta)
+			if param.Value() == value {
+				continue
+			}
+			if e := tuner.Error(); e < best {
+				best, improved = e, true
+				break
+			}
+			param.Set(value)
+		}
+	}
+	return best, improved
+}
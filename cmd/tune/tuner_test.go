@@ -0,0 +1,114 @@
+package main
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+var _ = Describe("Tuner", func() {
+	var defaults map[string]int
+
+	BeforeEach(func() {
+		defaults = map[string]int{}
+		for _, param := range utils.Params() {
+			defaults[param.Name] = param.Default
+		}
+	})
+
+	AfterEach(func() {
+		// LoadParams changes the defaults too
+		for _, param := range utils.Params() {
+			param.Default = defaults[param.Name]
+		}
+		utils.ResetParams()
+	})
+
+	labelled := func(fen string, result float64) position {
+		p, err := board.ParseFEN(fen)
+		Expect(err).
+			ToNot(HaveOccurred())
+		return position{p, result}
+	}
+
+	It("Maps scores to expected results with the sigmoid", func() {
+		tuner := newTuner(nil, solver.MaterialEvaluatorName)
+		Expect(tuner.sigmoid(0)).
+			To(Equal(0.5))
+		Expect(tuner.sigmoid(400)).
+			To(BeNumerically("~", 10.0/11, 1e-9))
+		Expect(tuner.sigmoid(-400)).
+			To(BeNumerically("~", 1.0/11, 1e-9))
+
+		tuner.k = 2
+		Expect(tuner.sigmoid(200)).
+			To(BeNumerically("~", 10.0/11, 1e-9))
+	})
+
+	It("Computes the mean squared error of the results", func() {
+		tuner := newTuner([]position{
+			labelled(board.StartFEN, 1),
+			labelled(board.StartFEN, 0.5),
+			labelled("4k3/8/8/8/8/8/4P3/4K3 w - - 0 1", 0),
+		}, solver.MaterialEvaluatorName)
+
+		pawn := 1 / (1 + math.Pow(10, -float64(utils.PawnValue)/400))
+		Expect(tuner.Error()).
+			To(BeNumerically("~", (0.25+0+pawn*pawn)/3, 1e-9))
+	})
+
+	It("Doesn't increase the error in a pass", func() {
+		tuner := newTuner([]position{
+			labelled(board.StartFEN, 0.5),
+			labelled("4k3/8/8/8/8/8/4P3/4K3 w - - 0 1", 1),
+			labelled("4k3/4p3/8/8/8/8/8/4K3 b - - 0 1", 0.5),
+			labelled("r3k3/8/8/8/8/8/8/4K2R w - - 0 1", 0.5),
+		}, solver.ClassicalEvaluatorName)
+
+		before := tuner.Error()
+		after, improved := tuner.Pass(before, 8)
+		Expect(after).
+			To(BeNumerically("<=", before))
+		Expect(tuner.Error()).
+			To(Equal(after))
+		Expect(improved).
+			To(Equal(after < before))
+	})
+
+	It("Writes parameters that load back the same", func() {
+		dir, err := ioutil.TempDir("", "tune")
+		Expect(err).
+			ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "params.txt")
+
+		utils.GetParam("Pawn Value").Set(123)
+		utils.GetParam("Knight Mobility EG").Set(-7)
+		Expect(writeParams(path)).
+			To(Succeed())
+		written := map[string]int{}
+		for _, param := range utils.Params() {
+			written[param.Name] = param.Value()
+		}
+
+		utils.ResetParams()
+		f, err := os.Open(path)
+		Expect(err).
+			ToNot(HaveOccurred())
+		defer f.Close()
+		Expect(utils.LoadParams(f)).
+			To(Succeed())
+		for _, param := range utils.Params() {
+			Expect(param.Value()).
+				To(Equal(written[param.Name]), param.Name)
+		}
+	})
+})
@@ -3,7 +3,6 @@ module github.com/mhv2109/uci-impl
 go 1.14
 
 require (
-	github.com/hashicorp/golang-lru v0.5.4
 	github.com/maxbrunsfeld/counterfeiter/v6 v6.2.3
 	github.com/notnil/chess v1.0.0
 	github.com/onsi/ginkgo v1.14.0
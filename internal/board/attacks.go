@@ -0,0 +1,117 @@
+package board
+
+// Attack tables of the non-sliding pieces, indexed by square.
+var (
+	knightAttacks [nSquares]Bitboard
+	kingAttacks   [nSquares]Bitboard
+	pawnAttacks   [2][nSquares]Bitboard // indexed by the color of the pawn
+)
+
+// between holds the squares strictly between two squares on a line, and line
+// the whole line through them, both empty if the squares aren't on a line.
+var (
+	between [nSquares][nSquares]Bitboard
+	line    [nSquares][nSquares]Bitboard
+)
+
+var (
+	rookDirections   = [4][2]int{{0, 1}, {1, 0}, {0, -1}, {-1, 0}}
+	bishopDirections = [4][2]int{{1, 1}, {1, -1}, {-1, -1}, {-1, 1}}
+)
+
+func init() {
+	for sq := A1; sq < NoSquare; sq++ {
+		knightAttacks[sq] = stepAttacks(sq, [][2]int{
+			{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}})
+		kingAttacks[sq] = stepAttacks(sq, [][2]int{
+			{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}})
+		pawnAttacks[White][sq] = stepAttacks(sq, [][2]int{{-1, 1}, {1, 1}})
+		pawnAttacks[Black][sq] = stepAttacks(sq, [][2]int{{-1, -1}, {1, -1}})
+	}
+
+	initMagics()
+
+	for a := A1; a < NoSquare; a++ {
+		for b := A1; b < NoSquare; b++ {
+			if a == b {
+				continue
+			}
+			directions := rookDirections
+			if a.File() != b.File() && a.Rank() != b.Rank() {
+				df, dr := a.File()-b.File(), a.Rank()-b.Rank()
+				if df != dr && df != -dr {
+					continue
+				}
+				directions = bishopDirections
+			}
+			between[a][b] = slidingAttacks(a, SquareBB(b), directions) &
+				slidingAttacks(b, SquareBB(a), directions)
+			line[a][b] = slidingAttacks(a, 0, directions)&slidingAttacks(b, 0, directions) |
+				SquareBB(a) | SquareBB(b)
+		}
+	}
+}
+
+func onBoard(file, rank int) bool {
+	return file >= 0 && file < 8 && rank >= 0 && rank < 8
+}
+
+func stepAttacks(sq Square, steps [][2]int) Bitboard {
+	var attacks Bitboard
+	for _, step := range steps {
+		if f, r := sq.File()+step[0], sq.Rank()+step[1]; onBoard(f, r) {
+			attacks |= SquareBB(NewSquare(f, r))
+		}
+	}
+	return attacks
+}
+
+// slidingAttacks returns the squares attacked from sq in directions, up to and
+// including the first occupied square in each.  It's slow, and only used to
+// fill the tables.
+func slidingAttacks(sq Square, occupied Bitboard, directions [4][2]int) Bitboard {
+	var attacks Bitboard
+	for _, d := range directions {
+		for f, r := sq.File()+d[0], sq.Rank()+d[1]; onBoard(f, r); f, r = f+d[0], r+d[1] {
+			s := SquareBB(NewSquare(f, r))
+			attacks |= s
+			if occupied&s != 0 {
+				break
+			}
+		}
+	}
+	return attacks
+}
+
+// KnightAttacks returns the squares a knight on sq attacks.
+func KnightAttacks(sq Square) Bitboard {
+	return knightAttacks[sq]
+}
+
+// KingAttacks returns the squares a king on sq attacks.
+func KingAttacks(sq Square) Bitboard {
+	return kingAttacks[sq]
+}
+
+// PawnAttacks returns the squares a pawn of color c on sq attacks.
+func PawnAttacks(c Color, sq Square) Bitboard {
+	return pawnAttacks[c][sq]
+}
+
+// RookAttacks returns the squares a rook on sq attacks, given the occupied
+// squares.
+func RookAttacks(sq Square, occupied Bitboard) Bitboard {
+	return rookMagics[sq].attacks[rookMagics[sq].index(occupied)]
+}
+
+// BishopAttacks returns the squares a bishop on sq attacks, given the occupied
+// squares.
+func BishopAttacks(sq Square, occupied Bitboard) Bitboard {
+	return bishopMagics[sq].attacks[bishopMagics[sq].index(occupied)]
+}
+
+// QueenAttacks returns the squares a queen on sq attacks, given the occupied
+// squares.
+func QueenAttacks(sq Square, occupied Bitboard) Bitboard {
+	return RookAttacks(sq, occupied) | BishopAttacks(sq, occupied)
+}
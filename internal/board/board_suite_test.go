@@ -0,0 +1,13 @@
+package board_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBoard(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Board Suite")
+}
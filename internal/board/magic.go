@@ -0,0 +1,95 @@
+package board
+
+import "math/bits"
+
+// magic maps the occupancy of the squares that can block a slider on a square
+// to an index of its attack table, by multiplying with a magic number that
+// gathers the relevant bits into the top bits of the product.
+type magic struct {
+	mask    Bitboard // squares that can block the slider, excluding the edges
+	magic   uint64
+	shift   uint
+	attacks []Bitboard
+}
+
+func (m *magic) index(occupied Bitboard) uint64 {
+	return uint64(occupied&m.mask) * m.magic >> m.shift
+}
+
+var rookMagics, bishopMagics [nSquares]magic
+
+// magicSeed is fixed, so finding the magics takes the same time in every run.
+const magicSeed = 0x2545F4914F6CDD1D
+
+func initMagics() {
+	rng := xorshift(magicSeed)
+	for sq := A1; sq < NoSquare; sq++ {
+		rookMagics[sq] = findMagic(sq, rookDirections, &rng)
+		bishopMagics[sq] = findMagic(sq, bishopDirections, &rng)
+	}
+}
+
+// findMagic searches for a magic number for a slider on sq by trial and
+// error, with random numbers with few bits set.
+func findMagic(sq Square, directions [4][2]int, rng *xorshift) magic {
+	mask := blockerMask(sq, directions)
+	n := mask.Count()
+
+	// every subset of the mask, with the attacks it allows
+	occupancies := make([]Bitboard, 0, 1<<uint(n))
+	attacks := make([]Bitboard, 0, 1<<uint(n))
+	for occupied := Bitboard(0); ; {
+		occupancies = append(occupancies, occupied)
+		attacks = append(attacks, slidingAttacks(sq, occupied, directions))
+		if occupied = (occupied - mask) & mask; occupied == 0 {
+			break
+		}
+	}
+
+	m := magic{mask: mask, shift: uint(64 - n), attacks: make([]Bitboard, 1<<uint(n))}
+	used := make([]int, len(m.attacks)) // the try each entry was set in
+	for try := 1; ; try++ {
+		m.magic = rng.next() & rng.next() & rng.next()
+		if bits.OnesCount64(uint64(mask)*m.magic>>56) < 6 {
+			continue
+		}
+
+		ok := true
+		for i, occupied := range occupancies {
+			index := m.index(occupied)
+			if used[index] != try {
+				used[index] = try
+				m.attacks[index] = attacks[i]
+			} else if m.attacks[index] != attacks[i] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return m
+		}
+	}
+}
+
+// blockerMask returns the squares that can block a slider on sq, which are
+// the squares it attacks on an empty board except the last in each direction.
+func blockerMask(sq Square, directions [4][2]int) Bitboard {
+	var mask Bitboard
+	for _, d := range directions {
+		f, r := sq.File()+d[0], sq.Rank()+d[1]
+		for ; onBoard(f+d[0], r+d[1]); f, r = f+d[0], r+d[1] {
+			mask |= SquareBB(NewSquare(f, r))
+		}
+	}
+	return mask
+}
+
+// xorshift is a small, fast pseudo random number generator.
+type xorshift uint64
+
+func (x *xorshift) next() uint64 {
+	*x ^= *x >> 12
+	*x ^= *x << 25
+	*x ^= *x >> 27
+	return uint64(*x) * 0x2545F4914F6CDD1D
+}
@@ -0,0 +1,69 @@
+package board
+
+// Move is a move in a position, with the origin and destination squares, the
+// promotion piece type and flags describing the move.  The low 16 bits
+// identify the move in its position.
+type Move uint32
+
+const (
+	moveCapture    Move = 1 << (16 + iota) // captures on the destination square
+	moveEnPassant                          // captures en passant
+	moveCastle                             // the king castles
+	moveDoublePush                         // a pawn moves two squares
+)
+
+// NoMove is the zero Move, which isn't a move.
+const NoMove Move = 0
+
+func newMove(from, to Square, promo PieceType, flags Move) Move {
+	return Move(from) | Move(to)<<6 | Move(promo)<<12 | flags
+}
+
+// From returns the origin square.
+func (m Move) From() Square {
+	return Square(m & 0x3f)
+}
+
+// To returns the destination square.
+func (m Move) To() Square {
+	return Square(m >> 6 & 0x3f)
+}
+
+// Promo returns the piece type a pawn promotes to, or NoPieceType.
+func (m Move) Promo() PieceType {
+	return PieceType(m >> 12 & 0x7)
+}
+
+// IsCapture returns true if the move captures, including en passant.
+func (m Move) IsCapture() bool {
+	return m&(moveCapture|moveEnPassant) != 0
+}
+
+// IsEnPassant returns true if the move captures en passant.
+func (m Move) IsEnPassant() bool {
+	return m&moveEnPassant != 0
+}
+
+// IsCastle returns true if the move castles.
+func (m Move) IsCastle() bool {
+	return m&moveCastle != 0
+}
+
+// IsNoisy returns true if the move is a capture or promotion, which change the
+// material balance.
+func (m Move) IsNoisy() bool {
+	return m.IsCapture() || m.Promo() != NoPieceType
+}
+
+// String returns the move in long algebraic notation, as in UCI, e.g. "e2e4",
+// "e1g1" or "e7e8q".
+func (m Move) String() string {
+	if m == NoMove {
+		return "0000"
+	}
+	s := m.From().String() + m.To().String()
+	if promo := m.Promo(); promo != NoPieceType {
+		s += NewPiece(Black, promo).String()
+	}
+	return s
+}
@@ -0,0 +1,221 @@
+package board
+
+import "fmt"
+
+// promotions are the piece types a pawn can promote to, best first.
+var promotions = [...]PieceType{Queen, Knight, Rook, Bishop}
+
+// LegalMoves appends the legal moves in the position to moves, and returns
+// the extended slice.
+func (pos *Position) LegalMoves(moves []Move) []Move {
+	return pos.legalMoves(moves, false)
+}
+
+// NoisyMoves appends the legal captures and promotions in the position to
+// moves, and returns the extended slice.
+func (pos *Position) NoisyMoves(moves []Move) []Move {
+	return pos.legalMoves(moves, true)
+}
+
+// HasLegalMoves returns true if the side to move has a legal move, i.e. isn't
+// checkmated or stalemated.
+func (pos *Position) HasLegalMoves() bool {
+	var buf [256]Move
+	return len(pos.LegalMoves(buf[:0])) > 0
+}
+
+// ParseMove returns the legal move in long algebraic notation s, as in UCI.
+func (pos *Position) ParseMove(s string) (Move, error) {
+	var buf [256]Move
+	for _, m := range pos.LegalMoves(buf[:0]) {
+		if m.String() == s {
+			return m, nil
+		}
+	}
+	return NoMove, fmt.Errorf("invalid move %q in %s", s, pos)
+}
+
+func (pos *Position) legalMoves(moves []Move, noisy bool) []Move {
+	start := len(moves)
+	moves = pos.pseudoLegalMoves(moves, noisy)
+
+	us, them := pos.turn, pos.turn.Other()
+	king := pos.King(us)
+	occupied := pos.AllOccupied()
+	checkers := pos.attackers(king, them, occupied)
+	pinned := pos.pinned(king, us)
+
+	legal := moves[:start]
+	for _, m := range moves[start:] {
+		if pos.isLegal(m, king, checkers, pinned) {
+			legal = append(legal, m)
+		}
+	}
+	return legal
+}
+
+// pinned returns the pieces of color us that can't leave the line between
+// their king and an enemy slider.
+func (pos *Position) pinned(king Square, us Color) Bitboard {
+	them := pos.colors[us.Other()]
+	snipers := (RookAttacks(king, 0)&(pos.pieces[Rook]|pos.pieces[Queen]) |
+		BishopAttacks(king, 0)&(pos.pieces[Bishop]|pos.pieces[Queen])) & them
+
+	var pinned Bitboard
+	occupied := pos.AllOccupied()
+	for snipers != 0 {
+		blockers := between[king][snipers.Pop()] & occupied
+		if blockers.Count() == 1 && blockers&pos.colors[us] != 0 {
+			pinned |= blockers
+		}
+	}
+	return pinned
+}
+
+// isLegal returns true if the pseudo legal move m doesn't leave the king of
+// the side to move, on king, in check.
+func (pos *Position) isLegal(m Move, king Square, checkers, pinned Bitboard) bool {
+	from, to := m.From(), m.To()
+	them := pos.turn.Other()
+
+	if from == king {
+		// castling is only generated through unattacked squares
+		return m.IsCastle() ||
+			!pos.attackedBy(to, them, pos.AllOccupied()&^SquareBB(from))
+	}
+
+	if m.IsEnPassant() {
+		// the captured pawn may have shielded the king, so check the sliders
+		capture := NewSquare(to.File(), from.Rank())
+		occupied := pos.AllOccupied()&^SquareBB(from)&^SquareBB(capture) | SquareBB(to)
+		return BishopAttacks(king, occupied)&(pos.pieces[Bishop]|pos.pieces[Queen])&pos.colors[them] == 0 &&
+			RookAttacks(king, occupied)&(pos.pieces[Rook]|pos.pieces[Queen])&pos.colors[them] == 0
+	}
+
+	if checkers != 0 {
+		if checkers.Count() > 1 {
+			return false
+		}
+		// capture or block the checker
+		checker := checkers.LSB()
+		if to != checker && !between[king][checker].Has(to) {
+			return false
+		}
+	}
+
+	return !pinned.Has(from) || line[from][king].Has(to)
+}
+
+// pseudoLegalMoves appends the moves that are legal unless they leave the
+// king in check, only captures and promotions if noisy.
+func (pos *Position) pseudoLegalMoves(moves []Move, noisy bool) []Move {
+	us, them := pos.turn, pos.turn.Other()
+	occupied := pos.AllOccupied()
+	targets := ^pos.colors[us]
+	if noisy {
+		targets = pos.colors[them]
+	}
+
+	moves = pos.pawnMoves(moves, noisy)
+
+	for _, t := range [...]PieceType{Knight, Bishop, Rook, Queen, King} {
+		for pieces := pos.Pieces(us, t); pieces != 0; {
+			from := pieces.Pop()
+			var attacks Bitboard
+			switch t {
+			case Knight:
+				attacks = knightAttacks[from]
+			case Bishop:
+				attacks = BishopAttacks(from, occupied)
+			case Rook:
+				attacks = RookAttacks(from, occupied)
+			case Queen:
+				attacks = QueenAttacks(from, occupied)
+			case King:
+				attacks = kingAttacks[from]
+			}
+			for attacks &= targets; attacks != 0; {
+				to := attacks.Pop()
+				var flags Move
+				if pos.squares[to] != NoPiece {
+					flags = moveCapture
+				}
+				moves = append(moves, newMove(from, to, NoPieceType, flags))
+			}
+		}
+	}
+
+	if !noisy {
+		moves = pos.castlingMoves(moves)
+	}
+	return moves
+}
+
+func (pos *Position) pawnMoves(moves []Move, noisy bool) []Move {
+	us, them := pos.turn, pos.turn.Other()
+	empty := ^pos.AllOccupied()
+	forward, promotionRank, startRank := 8, 7, 1
+	if us == Black {
+		forward, promotionRank, startRank = -8, 0, 6
+	}
+
+	for pawns := pos.Pieces(us, Pawn); pawns != 0; {
+		from := pawns.Pop()
+
+		if to := Square(int(from) + forward); empty.Has(to) {
+			if to.Rank() == promotionRank {
+				moves = appendPromotions(moves, from, to, 0)
+			} else if !noisy {
+				moves = append(moves, newMove(from, to, NoPieceType, 0))
+				if double := Square(int(to) + forward); from.Rank() == startRank && empty.Has(double) {
+					moves = append(moves, newMove(from, double, NoPieceType, moveDoublePush))
+				}
+			}
+		}
+
+		for attacks := pawnAttacks[us][from] & pos.colors[them]; attacks != 0; {
+			to := attacks.Pop()
+			if to.Rank() == promotionRank {
+				moves = appendPromotions(moves, from, to, moveCapture)
+			} else {
+				moves = append(moves, newMove(from, to, NoPieceType, moveCapture))
+			}
+		}
+
+		if pos.enPassant != NoSquare && pawnAttacks[us][from].Has(pos.enPassant) {
+			moves = append(moves, newMove(from, pos.enPassant, NoPieceType, moveEnPassant))
+		}
+	}
+	return moves
+}
+
+func appendPromotions(moves []Move, from, to Square, flags Move) []Move {
+	for _, promo := range promotions {
+		moves = append(moves, newMove(from, to, promo, flags))
+	}
+	return moves
+}
+
+// castlingMoves appends the castling moves, which must not start in, pass
+// through or end in check.
+func (pos *Position) castlingMoves(moves []Move) []Move {
+	us, them := pos.turn, pos.turn.Other()
+	kingSide, queenSide, king := WhiteKingSide, WhiteQueenSide, E1
+	if us == Black {
+		kingSide, queenSide, king = BlackKingSide, BlackQueenSide, E8
+	}
+	if pos.castling&(kingSide|queenSide) == 0 || pos.AttackedBy(king, them) {
+		return moves
+	}
+
+	occupied := pos.AllOccupied()
+	if pos.castling&kingSide != 0 && between[king][king+3]&occupied == 0 &&
+		!pos.AttackedBy(king+1, them) && !pos.AttackedBy(king+2, them) {
+		moves = append(moves, newMove(king, king+2, NoPieceType, moveCastle))
+	}
+	if pos.castling&queenSide != 0 && between[king][king-4]&occupied == 0 &&
+		!pos.AttackedBy(king-1, them) && !pos.AttackedBy(king-2, them) {
+		moves = append(moves, newMove(king, king-2, NoPieceType, moveCastle))
+	}
+	return moves
+}
@@ -0,0 +1,51 @@
+package board_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/mhv2109/uci-impl/internal/board"
+)
+
+var _ = Describe("Move generation", func() {
+	It("Generates only captures and promotions as noisy moves", func() {
+		pos, _ := ParseFEN("4k3/1P6/8/3p4/4P3/8/8/4K3 w - - 0 1")
+		var moves []string
+		for _, m := range pos.NoisyMoves(nil) {
+			moves = append(moves, m.String())
+			Expect(m.IsNoisy()).
+				To(BeTrue())
+		}
+		Expect(moves).
+			To(ConsistOf("e4d5", "b7b8q", "b7b8n", "b7b8r", "b7b8b"))
+	})
+
+	It("Parses moves in long algebraic notation", func() {
+		pos := NewPosition()
+		m, err := pos.ParseMove("e2e4")
+		Expect(err).
+			ToNot(HaveOccurred())
+		Expect(m.From()).
+			To(Equal(E2))
+		Expect(m.To()).
+			To(Equal(E4))
+
+		_, err = pos.ParseMove("e2e5")
+		Expect(err).
+			To(HaveOccurred())
+	})
+
+	It("Detects checkmate and stalemate", func() {
+		mate, _ := ParseFEN("R5k1/5ppp/8/8/8/8/5PPP/6K1 b - - 0 1")
+		Expect(mate.InCheck()).
+			To(BeTrue())
+		Expect(mate.HasLegalMoves()).
+			To(BeFalse())
+
+		stalemate, _ := ParseFEN("7k/5Q2/6K1/8/8/8/8/8 b - - 0 1")
+		Expect(stalemate.InCheck()).
+			To(BeFalse())
+		Expect(stalemate.HasLegalMoves()).
+			To(BeFalse())
+	})
+})
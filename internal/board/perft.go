@@ -0,0 +1,59 @@
+package board
+
+import "sort"
+
+// PerftResult is the number of leaf nodes of the move tree below a root move.
+type PerftResult struct {
+	Move  Move
+	Nodes int
+}
+
+// Perft counts the leaf nodes of the move tree of pos to depth, to check the
+// move generator against known results and measure its speed.
+func (pos *Position) Perft(depth int) int {
+	if depth < 1 {
+		return 1
+	}
+	return pos.perft(depth, make([][]Move, depth))
+}
+
+func (pos *Position) perft(depth int, buffers [][]Move) int {
+	moves := pos.LegalMoves(buffers[depth-1][:0])
+	buffers[depth-1] = moves
+	if depth == 1 {
+		return len(moves)
+	}
+
+	nodes := 0
+	for _, m := range moves {
+		pos.MakeMove(m)
+		nodes += pos.perft(depth-1, buffers)
+		pos.UnmakeMove()
+	}
+	return nodes
+}
+
+// Divide returns the perft count to depth below each legal move of pos,
+// ordered by move, to find the moves where the move generator goes wrong.
+func (pos *Position) Divide(depth int) []PerftResult {
+	if depth < 1 {
+		return nil
+	}
+
+	buffers := make([][]Move, depth)
+	var results []PerftResult
+	for _, m := range pos.LegalMoves(nil) {
+		nodes := 1
+		if depth > 1 {
+			pos.MakeMove(m)
+			nodes = pos.perft(depth-1, buffers)
+			pos.UnmakeMove()
+		}
+		results = append(results, PerftResult{m, nodes})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Move.String() < results[j].Move.String()
+	})
+	return results
+}
@@ -0,0 +1,77 @@
+package board_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/mhv2109/uci-impl/internal/board"
+)
+
+var _ = Describe("Perft", func() {
+	// well known positions with their perft results, from the Chess
+	// Programming Wiki
+	expectPerft := func(fen string, nodes ...int) {
+		pos, err := ParseFEN(fen)
+		Expect(err).
+			ToNot(HaveOccurred())
+		for i, n := range nodes {
+			Expect(pos.Perft(i+1)).
+				To(Equal(n), "depth %d", i+1)
+		}
+		Expect(pos.String()).
+			To(Equal(fen))
+	}
+
+	It("Counts the moves of the start position", func() {
+		expectPerft(StartFEN, 20, 400, 8902, 197281)
+	})
+
+	It("Counts castling, en passant and promotions", func() {
+		expectPerft("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+			48, 2039, 97862)
+	})
+
+	It("Counts pins and en passant discovered checks", func() {
+		expectPerft("8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 14, 191, 2812, 43238, 674624)
+	})
+
+	It("Counts checks and promotions with capture", func() {
+		expectPerft("r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+			6, 264, 9467, 422333)
+		expectPerft("rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+			44, 1486, 62379)
+		expectPerft("r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10",
+			46, 2079, 89890)
+	})
+
+	It("Counts the root position at depth 0", func() {
+		Expect(NewPosition().Perft(0)).
+			To(Equal(1))
+		Expect(NewPosition().Divide(0)).
+			To(BeEmpty())
+	})
+
+	It("Divides the count by root move", func() {
+		pos, _ := ParseFEN("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+		results := pos.Divide(2)
+		Expect(results).
+			To(HaveLen(48))
+
+		total := 0
+		nodes := map[string]int{}
+		for i, r := range results {
+			if i > 0 {
+				Expect(r.Move.String() > results[i-1].Move.String()).
+					To(BeTrue())
+			}
+			nodes[r.Move.String()] = r.Nodes
+			total += r.Nodes
+		}
+		Expect(total).
+			To(Equal(2039))
+		Expect(nodes).
+			To(HaveKeyWithValue("e1g1", 43))
+		Expect(nodes).
+			To(HaveKeyWithValue("d5e6", 46))
+	})
+})
@@ -0,0 +1,523 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StartFEN is the FEN of the start position.
+const StartFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+// Position is a chess position, along with the moves made to reach it so they
+// can be unmade and repetitions detected.  The en passant square is only set if
+// an en passant capture is possible, so positions that can't differ in their
+// moves have the same key.
+type Position struct {
+	squares   [nSquares]Piece
+	pieces    [nPieceTypes]Bitboard // indexed by piece type
+	colors    [2]Bitboard           // indexed by color
+	turn      Color
+	castling  CastlingRights
+	enPassant Square
+	halfMoves int    // half moves since the last capture or pawn move
+	fullMoves int    // starting at 1, incremented after Black moves
+	key       uint64 // Zobrist key
+	pawnKey   uint64 // Zobrist key of the pawns only
+	history   []undo // one for each move made
+}
+
+// undo is the state a move overwrites, to unmake it.
+type undo struct {
+	move      Move
+	captured  Piece
+	castling  CastlingRights
+	enPassant Square
+	halfMoves int
+	key       uint64
+}
+
+// castlingMask holds the castling rights kept when a piece moves from or to a
+// square.
+var castlingMask [nSquares]CastlingRights
+
+func init() {
+	for sq := range castlingMask {
+		castlingMask[sq] = WhiteKingSide | WhiteQueenSide | BlackKingSide | BlackQueenSide
+	}
+	castlingMask[E1] &^= WhiteKingSide | WhiteQueenSide
+	castlingMask[H1] &^= WhiteKingSide
+	castlingMask[A1] &^= WhiteQueenSide
+	castlingMask[E8] &^= BlackKingSide | BlackQueenSide
+	castlingMask[H8] &^= BlackKingSide
+	castlingMask[A8] &^= BlackQueenSide
+}
+
+// NewPosition returns the start position.
+func NewPosition() *Position {
+	pos, err := ParseFEN(StartFEN)
+	if err != nil {
+		panic(err)
+	}
+	return pos
+}
+
+// ParseFEN returns the position described by fen.  The move counters are
+// optional.
+func ParseFEN(fen string) (*Position, error) {
+	fields := strings.Fields(fen)
+	if len(fields) < 4 || len(fields) > 6 {
+		return nil, fmt.Errorf("invalid FEN %q: expected 4 to 6 fields", fen)
+	}
+
+	pos := &Position{enPassant: NoSquare, fullMoves: 1}
+
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return nil, fmt.Errorf("invalid FEN %q: expected 8 ranks", fen)
+	}
+	for i, rank := range ranks {
+		file := 0
+		for _, c := range rank {
+			if c >= '1' && c <= '8' {
+				file += int(c - '0')
+				continue
+			}
+			p := strings.IndexRune(pieceChars, c)
+			if p < 1 || file > 7 {
+				return nil, fmt.Errorf("invalid FEN %q: invalid rank %q", fen, rank)
+			}
+			pos.put(NewSquare(file, 7-i), Piece(p))
+			file++
+		}
+		if file != 8 {
+			return nil, fmt.Errorf("invalid FEN %q: invalid rank %q", fen, rank)
+		}
+	}
+	for c := White; c <= Black; c++ {
+		if pos.Pieces(c, King).Count() != 1 {
+			return nil, fmt.Errorf("invalid FEN %q: expected one king of each color", fen)
+		}
+	}
+
+	switch fields[1] {
+	case "w":
+	case "b":
+		pos.turn = Black
+		pos.key ^= zobristBlack
+	default:
+		return nil, fmt.Errorf("invalid FEN %q: invalid side to move %q", fen, fields[1])
+	}
+
+	if fields[2] != "-" {
+		for _, c := range fields[2] {
+			i := strings.IndexRune("KQkq", c)
+			if i < 0 {
+				return nil, fmt.Errorf("invalid FEN %q: invalid castling rights %q", fen, fields[2])
+			}
+			pos.castling |= 1 << uint(i)
+		}
+	}
+	pos.castling &= pos.possibleCastling()
+	pos.key ^= zobristCastling[pos.castling]
+
+	if fields[3] != "-" {
+		sq, err := ParseSquare(fields[3])
+		if err != nil || (sq.Rank() != 2 && sq.Rank() != 5) {
+			return nil, fmt.Errorf("invalid FEN %q: invalid en passant square %q", fen, fields[3])
+		}
+		pos.setEnPassant(sq)
+	}
+
+	if len(fields) > 4 {
+		halfMoves, err := strconv.Atoi(fields[4])
+		if err != nil || halfMoves < 0 {
+			return nil, fmt.Errorf("invalid FEN %q: invalid half move clock %q", fen, fields[4])
+		}
+		pos.halfMoves = halfMoves
+	}
+	if len(fields) > 5 {
+		fullMoves, err := strconv.Atoi(fields[5])
+		if err != nil || fullMoves < 1 {
+			return nil, fmt.Errorf("invalid FEN %q: invalid move number %q", fen, fields[5])
+		}
+		pos.fullMoves = fullMoves
+	}
+
+	return pos, nil
+}
+
+// possibleCastling returns the castling rights the kings and rooks are placed
+// for.
+func (pos *Position) possibleCastling() CastlingRights {
+	var rights CastlingRights
+	if pos.squares[E1] == WhiteKing {
+		if pos.squares[H1] == WhiteRook {
+			rights |= WhiteKingSide
+		}
+		if pos.squares[A1] == WhiteRook {
+			rights |= WhiteQueenSide
+		}
+	}
+	if pos.squares[E8] == BlackKing {
+		if pos.squares[H8] == BlackRook {
+			rights |= BlackKingSide
+		}
+		if pos.squares[A8] == BlackRook {
+			rights |= BlackQueenSide
+		}
+	}
+	return rights
+}
+
+// String returns the FEN of the position.
+func (pos *Position) String() string {
+	var b strings.Builder
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			piece := pos.squares[NewSquare(file, rank)]
+			if piece == NoPiece {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				b.WriteByte(byte('0' + empty))
+				empty = 0
+			}
+			b.WriteString(piece.String())
+		}
+		if empty > 0 {
+			b.WriteByte(byte('0' + empty))
+		}
+		if rank > 0 {
+			b.WriteByte('/')
+		}
+	}
+	fmt.Fprintf(&b, " %s %s %s %d %d", pos.turn, pos.castling, pos.enPassant, pos.halfMoves, pos.fullMoves)
+	return b.String()
+}
+
+// Copy returns a copy of the position, which can make and unmake moves
+// independently.
+func (pos *Position) Copy() *Position {
+	c := *pos
+	c.history = append([]undo(nil), pos.history...)
+	return &c
+}
+
+// Turn returns the side to move.
+func (pos *Position) Turn() Color {
+	return pos.turn
+}
+
+// Piece returns the piece on sq, or NoPiece.
+func (pos *Position) Piece(sq Square) Piece {
+	return pos.squares[sq]
+}
+
+// Pieces returns the squares of the pieces of color c and type t.
+func (pos *Position) Pieces(c Color, t PieceType) Bitboard {
+	return pos.pieces[t] & pos.colors[c]
+}
+
+// PiecesOfType returns the squares of the pieces of type t of both colors.
+func (pos *Position) PiecesOfType(t PieceType) Bitboard {
+	return pos.pieces[t]
+}
+
+// Occupied returns the squares of the pieces of color c.
+func (pos *Position) Occupied(c Color) Bitboard {
+	return pos.colors[c]
+}
+
+// AllOccupied returns the squares of all pieces.
+func (pos *Position) AllOccupied() Bitboard {
+	return pos.colors[White] | pos.colors[Black]
+}
+
+// King returns the square of the king of color c.
+func (pos *Position) King(c Color) Square {
+	return pos.Pieces(c, King).LSB()
+}
+
+// Castling returns the remaining castling rights.
+func (pos *Position) Castling() CastlingRights {
+	return pos.castling
+}
+
+// EnPassant returns the square a pawn can capture en passant on, or NoSquare.
+func (pos *Position) EnPassant() Square {
+	return pos.enPassant
+}
+
+// HalfMoveClock returns the number of half moves since the last capture or
+// pawn move, for the fifty-move rule.
+func (pos *Position) HalfMoveClock() int {
+	return pos.halfMoves
+}
+
+// Key returns the Zobrist key of the position.
+func (pos *Position) Key() uint64 {
+	return pos.key
+}
+
+// PawnKey returns the Zobrist key of the pawns of both sides, which changes
+// only when a pawn moves, is captured or promotes.  Positions without pawns
+// have the key 0.
+func (pos *Position) PawnKey() uint64 {
+	return pos.pawnKey
+}
+
+// Ply returns the number of moves made since the position was parsed.
+func (pos *Position) Ply() int {
+	return len(pos.history)
+}
+
+// MovesSince returns the moves made since ply, oldest first.
+func (pos *Position) MovesSince(ply int) []Move {
+	moves := make([]Move, 0, len(pos.history)-ply)
+	for _, u := range pos.history[ply:] {
+		moves = append(moves, u.move)
+	}
+	return moves
+}
+
+// AttackedBy returns true if sq is attacked by a piece of color c.
+func (pos *Position) AttackedBy(sq Square, c Color) bool {
+	return pos.attackedBy(sq, c, pos.AllOccupied())
+}
+
+func (pos *Position) attackedBy(sq Square, c Color, occupied Bitboard) bool {
+	them := pos.colors[c]
+	return pawnAttacks[c.Other()][sq]&pos.pieces[Pawn]&them != 0 ||
+		knightAttacks[sq]&pos.pieces[Knight]&them != 0 ||
+		kingAttacks[sq]&pos.pieces[King]&them != 0 ||
+		BishopAttacks(sq, occupied)&(pos.pieces[Bishop]|pos.pieces[Queen])&them != 0 ||
+		RookAttacks(sq, occupied)&(pos.pieces[Rook]|pos.pieces[Queen])&them != 0
+}
+
+// attackers returns the pieces of color c attacking sq.
+func (pos *Position) attackers(sq Square, c Color, occupied Bitboard) Bitboard {
+	return (pawnAttacks[c.Other()][sq]&pos.pieces[Pawn] |
+		knightAttacks[sq]&pos.pieces[Knight] |
+		kingAttacks[sq]&pos.pieces[King] |
+		BishopAttacks(sq, occupied)&(pos.pieces[Bishop]|pos.pieces[Queen]) |
+		RookAttacks(sq, occupied)&(pos.pieces[Rook]|pos.pieces[Queen])) & pos.colors[c]
+}
+
+// InCheck returns true if the side to move is in check.
+func (pos *Position) InCheck() bool {
+	return pos.AttackedBy(pos.King(pos.turn), pos.turn.Other())
+}
+
+// IsRepetition returns true if the position repeats a position since the last
+// capture or pawn move.  Repeating a position reached after ply root, i.e. by
+// the search, counts at once, as the side that could avoid it doesn't need to
+// repeat a third time, while positions up to root must have occurred twice
+// before.
+func (pos *Position) IsRepetition(root int) bool {
+	n := len(pos.history)
+	repetitions := 0
+	// positions can only repeat with the same side to move
+	for i := 2; i <= pos.halfMoves && i <= n; i += 2 {
+		if pos.history[n-i].key == pos.key {
+			if repetitions++; n-i > root || repetitions >= 2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (pos *Position) put(sq Square, piece Piece) {
+	b := SquareBB(sq)
+	pos.squares[sq] = piece
+	pos.pieces[piece.Type()] |= b
+	pos.colors[piece.Color()] |= b
+	pos.key ^= zobristPieces[piece][sq]
+	if piece.Type() == Pawn {
+		pos.pawnKey ^= zobristPieces[piece][sq]
+	}
+}
+
+func (pos *Position) remove(sq Square) {
+	piece := pos.squares[sq]
+	b := SquareBB(sq)
+	pos.squares[sq] = NoPiece
+	pos.pieces[piece.Type()] &^= b
+	pos.colors[piece.Color()] &^= b
+	pos.key ^= zobristPieces[piece][sq]
+	if piece.Type() == Pawn {
+		pos.pawnKey ^= zobristPieces[piece][sq]
+	}
+}
+
+func (pos *Position) move(from, to Square) {
+	piece := pos.squares[from]
+	b := SquareBB(from) | SquareBB(to)
+	pos.squares[from], pos.squares[to] = NoPiece, piece
+	pos.pieces[piece.Type()] ^= b
+	pos.colors[piece.Color()] ^= b
+	pos.key ^= zobristPieces[piece][from] ^ zobristPieces[piece][to]
+	if piece.Type() == Pawn {
+		pos.pawnKey ^= zobristPieces[piece][from] ^ zobristPieces[piece][to]
+	}
+}
+
+// setEnPassant sets the en passant square behind a pawn that just moved two
+// squares, if a pawn of the side to move can capture it.
+func (pos *Position) setEnPassant(sq Square) {
+	if pawnAttacks[pos.turn.Other()][sq]&pos.Pieces(pos.turn, Pawn) != 0 {
+		pos.enPassant = sq
+		pos.key ^= zobristEnPassant[sq.File()]
+	}
+}
+
+// MakeMove makes move, which must be legal in the position.
+func (pos *Position) MakeMove(m Move) {
+	from, to := m.From(), m.To()
+	us := pos.turn
+	captured := pos.squares[to]
+
+	pos.history = append(pos.history, undo{m, captured, pos.castling, pos.enPassant, pos.halfMoves, pos.key})
+
+	if pos.enPassant != NoSquare {
+		pos.key ^= zobristEnPassant[pos.enPassant.File()]
+		pos.enPassant = NoSquare
+	}
+	pos.halfMoves++
+
+	if m.IsEnPassant() {
+		capture := NewSquare(to.File(), from.Rank())
+		pos.history[len(pos.history)-1].captured = pos.squares[capture]
+		pos.remove(capture)
+	} else if captured != NoPiece {
+		pos.remove(to)
+		pos.halfMoves = 0
+	}
+
+	if m.IsCastle() {
+		rookFrom, rookTo := castlingRook(to)
+		pos.move(rookFrom, rookTo)
+	}
+
+	pos.move(from, to)
+
+	if pos.squares[to].Type() == Pawn {
+		pos.halfMoves = 0
+		if promo := m.Promo(); promo != NoPieceType {
+			pos.remove(to)
+			pos.put(to, NewPiece(us, promo))
+		}
+	}
+
+	if rights := pos.castling & castlingMask[from] & castlingMask[to]; rights != pos.castling {
+		pos.key ^= zobristCastling[pos.castling] ^ zobristCastling[rights]
+		pos.castling = rights
+	}
+
+	if us == Black {
+		pos.fullMoves++
+	}
+	pos.turn = us.Other()
+	pos.key ^= zobristBlack
+
+	if m&moveDoublePush != 0 {
+		pos.setEnPassant(NewSquare(from.File(), (from.Rank()+to.Rank())/2))
+	}
+}
+
+// UnmakeMove unmakes the last move made.
+func (pos *Position) UnmakeMove() {
+	u := pos.history[len(pos.history)-1]
+	pos.history = pos.history[:len(pos.history)-1]
+
+	pos.turn = pos.turn.Other()
+	us := pos.turn
+	if us == Black {
+		pos.fullMoves--
+	}
+
+	m := u.move
+	from, to := m.From(), m.To()
+	if m.Promo() != NoPieceType {
+		pos.remove(to)
+		pos.put(from, NewPiece(us, Pawn))
+	} else {
+		pos.move(to, from)
+	}
+
+	if m.IsCastle() {
+		rookFrom, rookTo := castlingRook(to)
+		pos.move(rookTo, rookFrom)
+	}
+
+	if m.IsEnPassant() {
+		pos.put(NewSquare(to.File(), from.Rank()), u.captured)
+	} else if u.captured != NoPiece {
+		pos.put(to, u.captured)
+	}
+
+	pos.castling = u.castling
+	pos.enPassant = u.enPassant
+	pos.halfMoves = u.halfMoves
+	pos.key = u.key
+}
+
+// MakeNullMove passes the turn to the other side, for null move pruning.  The
+// position must not be in check.  The half-move clock is reset, so positions
+// before the null move don't count as repeated.
+func (pos *Position) MakeNullMove() {
+	pos.history = append(pos.history, undo{NoMove, NoPiece, pos.castling, pos.enPassant, pos.halfMoves, pos.key})
+
+	if pos.enPassant != NoSquare {
+		pos.key ^= zobristEnPassant[pos.enPassant.File()]
+		pos.enPassant = NoSquare
+	}
+	pos.halfMoves = 0
+
+	if pos.turn == Black {
+		pos.fullMoves++
+	}
+	pos.turn = pos.turn.Other()
+	pos.key ^= zobristBlack
+}
+
+// UnmakeNullMove unmakes the null move made last.
+func (pos *Position) UnmakeNullMove() {
+	u := pos.history[len(pos.history)-1]
+	pos.history = pos.history[:len(pos.history)-1]
+
+	pos.turn = pos.turn.Other()
+	if pos.turn == Black {
+		pos.fullMoves--
+	}
+
+	pos.enPassant = u.enPassant
+	pos.halfMoves = u.halfMoves
+	pos.key = u.key
+}
+
+// LastMove returns the move made last, or NoMove if there is none or it was a
+// null move.
+func (pos *Position) LastMove() Move {
+	if len(pos.history) == 0 {
+		return NoMove
+	}
+	return pos.history[len(pos.history)-1].move
+}
+
+// castlingRook returns the squares the rook moves from and to when the king
+// castles to kingTo.
+func castlingRook(kingTo Square) (Square, Square) {
+	switch kingTo {
+	case G1:
+		return H1, F1
+	case C1:
+		return A1, D1
+	case G8:
+		return H8, F8
+	}
+	return A8, D8
+}
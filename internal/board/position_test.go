@@ -0,0 +1,181 @@
+package board_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/mhv2109/uci-impl/internal/board"
+)
+
+var _ = Describe("Position", func() {
+	parse := func(fen string) *Position {
+		pos, err := ParseFEN(fen)
+		Expect(err).
+			ToNot(HaveOccurred())
+		return pos
+	}
+
+	play := func(pos *Position, moves ...string) *Position {
+		for _, s := range moves {
+			m, err := pos.ParseMove(s)
+			Expect(err).
+				ToNot(HaveOccurred())
+			pos.MakeMove(m)
+		}
+		return pos
+	}
+
+	It("Reads and writes FEN", func() {
+		fen := "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R b Kq - 3 17"
+		Expect(parse(fen).String()).
+			To(Equal(fen))
+		Expect(parse("8/8/8/8/8/8/8/K6k w - -").String()).
+			To(Equal("8/8/8/8/8/8/8/K6k w - - 0 1"))
+	})
+
+	It("Rejects invalid FEN", func() {
+		for _, fen := range []string{
+			"",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP w KQkq - 0 1",
+			"rnbqkbnr/pppppppp/9/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQ1BNR w KQkq - 0 1",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkx - 0 1",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq e4 0 1",
+		} {
+			_, err := ParseFEN(fen)
+			Expect(err).
+				To(HaveOccurred(), fen)
+		}
+	})
+
+	It("Only keeps the en passant square if a capture is possible", func() {
+		Expect(play(NewPosition(), "e2e4").EnPassant()).
+			To(Equal(NoSquare))
+		Expect(play(NewPosition(), "e2e4", "g8f6", "e4e5", "d7d5").EnPassant()).
+			To(Equal(D6))
+	})
+
+	It("Unmakes moves", func() {
+		pos := parse("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+		fen, key, pawnKey := pos.String(), pos.Key(), pos.PawnKey()
+		for _, m := range pos.LegalMoves(nil) {
+			pos.MakeMove(m)
+			Expect(pos.Key()).
+				To(Equal(parse(pos.String()).Key()), m.String())
+			Expect(pos.PawnKey()).
+				To(Equal(parse(pos.String()).PawnKey()), m.String())
+			pos.UnmakeMove()
+			Expect(pos.String()).
+				To(Equal(fen))
+			Expect(pos.Key()).
+				To(Equal(key))
+			Expect(pos.PawnKey()).
+				To(Equal(pawnKey))
+		}
+	})
+
+	It("Makes and unmakes null moves", func() {
+		pos := play(NewPosition(), "e2e4", "g8f6", "e4e5", "d7d5")
+		fen, key := pos.String(), pos.Key()
+
+		pos.MakeNullMove()
+		Expect(pos.String()).
+			To(Equal("rnbqkb1r/ppp1pppp/5n2/3pP3/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3"))
+		Expect(pos.Key()).
+			To(Equal(parse(pos.String()).Key()))
+		Expect(pos.LastMove()).
+			To(Equal(NoMove))
+
+		pos.UnmakeNullMove()
+		Expect(pos.String()).
+			To(Equal(fen))
+		Expect(pos.Key()).
+			To(Equal(key))
+		Expect(pos.LastMove().String()).
+			To(Equal("d7d5"))
+	})
+
+	It("Gives transpositions the same key", func() {
+		a := play(NewPosition(), "g1f3", "b8c6", "b1c3")
+		b := play(NewPosition(), "b1c3", "b8c6", "g1f3")
+		Expect(a.Key()).
+			To(Equal(b.Key()))
+		Expect(play(NewPosition(), "g1f3", "g8f6", "f3g1").Key()).
+			ToNot(Equal(NewPosition().Key()))
+	})
+
+	It("Keys the pawns only", func() {
+		Expect(play(NewPosition(), "g1f3", "g8f6").PawnKey()).
+			To(Equal(NewPosition().PawnKey()))
+		Expect(play(NewPosition(), "e2e4").PawnKey()).
+			ToNot(Equal(NewPosition().PawnKey()))
+		Expect(parse("4k3/8/8/8/8/8/8/4K3 w - - 0 1").PawnKey()).
+			To(BeZero())
+
+		// a white and a black pawn on the same file don't cancel out
+		a := parse("4k3/p7/8/8/8/8/P7/4K3 w - - 0 1")
+		b := parse("4k3/8/p7/8/8/P7/8/4K3 w - - 0 1")
+		Expect(a.PawnKey()).
+			ToNot(BeZero())
+		Expect(a.PawnKey()).
+			ToNot(Equal(b.PawnKey()))
+	})
+
+	It("Changes the key with the castling rights", func() {
+		pos := play(NewPosition(), "g1f3", "g8f6", "h1g1", "f6g8", "g1h1", "g8f6", "f3g1", "f6g8")
+		Expect(pos.Castling()).
+			To(Equal(WhiteQueenSide | BlackKingSide | BlackQueenSide))
+		Expect(pos.Key()).
+			ToNot(Equal(NewPosition().Key()))
+	})
+
+	It("Changes the key with the en passant square", func() {
+		a := play(NewPosition(), "e2e4", "g8f6", "e4e5", "d7d5")
+		b := parse("rnbqkb1r/ppp1pppp/5n2/3pP3/8/8/PPPP1PPP/RNBQKBNR w KQkq - 0 3")
+		Expect(a.Key()).
+			ToNot(Equal(b.Key()))
+	})
+
+	It("Tracks the half move clock", func() {
+		pos := play(NewPosition(), "g1f3", "g8f6", "f3g1")
+		Expect(pos.HalfMoveClock()).
+			To(Equal(3))
+		Expect(play(pos, "e7e5").HalfMoveClock()).
+			To(BeZero())
+	})
+
+	Describe("Repetitions", func() {
+		It("Counts a repetition after the root at once", func() {
+			pos := NewPosition()
+			play(pos, "g1f3", "g8f6", "f3g1", "f6g8")
+			// the root was only reached once in the game
+			Expect(pos.IsRepetition(0)).
+				To(BeFalse())
+			play(pos, "g1f3")
+			Expect(pos.IsRepetition(0)).
+				To(BeTrue())
+		})
+
+		It("Needs two earlier occurrences of a position up to the root", func() {
+			pos := play(NewPosition(), "g1f3", "g8f6", "f3g1", "f6g8")
+			root := pos.Ply()
+			Expect(pos.IsRepetition(root)).
+				To(BeFalse())
+			play(pos, "b1c3", "b8c6", "c3b1", "c6b8")
+			Expect(pos.IsRepetition(root)).
+				To(BeTrue())
+		})
+
+		It("Looks back to the last capture or pawn move", func() {
+			pos := play(NewPosition(), "g1f3", "g8f6", "f3g1", "e7e5", "g1f3", "f6g8", "f3g1", "g8f6")
+			Expect(pos.HalfMoveClock()).
+				To(Equal(4))
+			// repeats the position after e7e5, which has no en passant capture
+			Expect(pos.IsRepetition(0)).
+				To(BeTrue())
+			Expect(pos.IsRepetition(pos.Ply())).
+				To(BeFalse())
+		})
+	})
+})
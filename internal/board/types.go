@@ -0,0 +1,251 @@
+// Package board represents chess positions with bitboards, and generates,
+// makes and unmakes moves in them quickly enough for the search.  Squares,
+// pieces and piece types are numbered as in github.com/notnil/chess, so they
+// convert directly; positions and moves convert through FEN and long algebraic
+// notation.
+package board
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Color is the color of a side, usable as an index.
+type Color uint8
+
+const (
+	White Color = iota
+	Black
+)
+
+// Other returns the opposing color.
+func (c Color) Other() Color {
+	return c ^ 1
+}
+
+func (c Color) String() string {
+	if c == Black {
+		return "b"
+	}
+	return "w"
+}
+
+// PieceType is the type of a piece, regardless of color.
+type PieceType uint8
+
+const (
+	NoPieceType PieceType = iota
+	King
+	Queen
+	Rook
+	Bishop
+	Knight
+	Pawn
+)
+
+const nPieceTypes = 7 // including NoPieceType
+
+// Piece is a piece of a color.
+type Piece uint8
+
+const (
+	NoPiece Piece = iota
+	WhiteKing
+	WhiteQueen
+	WhiteRook
+	WhiteBishop
+	WhiteKnight
+	WhitePawn
+	BlackKing
+	BlackQueen
+	BlackRook
+	BlackBishop
+	BlackKnight
+	BlackPawn
+)
+
+const nPieces = 13 // including NoPiece
+
+// pieceChars are the FEN characters of the pieces.
+const pieceChars = " KQRBNPkqrbnp"
+
+// NewPiece returns the piece of type t and color c.
+func NewPiece(c Color, t PieceType) Piece {
+	return Piece(uint8(c)*6 + uint8(t))
+}
+
+// Color returns the color of the piece, which must not be NoPiece.
+func (p Piece) Color() Color {
+	return Color((p - 1) / 6)
+}
+
+// Type returns the type of the piece.
+func (p Piece) Type() PieceType {
+	if p == NoPiece {
+		return NoPieceType
+	}
+	return PieceType((p-1)%6 + 1)
+}
+
+func (p Piece) String() string {
+	return string(pieceChars[p])
+}
+
+// Square is a square of the board, from A1 to H8 rank by rank.
+type Square uint8
+
+const (
+	A1 Square = iota
+	B1
+	C1
+	D1
+	E1
+	F1
+	G1
+	H1
+	A2
+	B2
+	C2
+	D2
+	E2
+	F2
+	G2
+	H2
+	A3
+	B3
+	C3
+	D3
+	E3
+	F3
+	G3
+	H3
+	A4
+	B4
+	C4
+	D4
+	E4
+	F4
+	G4
+	H4
+	A5
+	B5
+	C5
+	D5
+	E5
+	F5
+	G5
+	H5
+	A6
+	B6
+	C6
+	D6
+	E6
+	F6
+	G6
+	H6
+	A7
+	B7
+	C7
+	D7
+	E7
+	F7
+	G7
+	H7
+	A8
+	B8
+	C8
+	D8
+	E8
+	F8
+	G8
+	H8
+	NoSquare
+)
+
+const nSquares = 64
+
+// NewSquare returns the square on file and rank, both from 0 to 7.
+func NewSquare(file, rank int) Square {
+	return Square(rank*8 + file)
+}
+
+// File returns the file of the square, 0 for the a-file.
+func (sq Square) File() int {
+	return int(sq) & 7
+}
+
+// Rank returns the rank of the square, 0 for the first rank.
+func (sq Square) Rank() int {
+	return int(sq) >> 3
+}
+
+func (sq Square) String() string {
+	if sq >= NoSquare {
+		return "-"
+	}
+	return string([]byte{byte('a' + sq.File()), byte('1' + sq.Rank())})
+}
+
+// ParseSquare parses a square in algebraic notation, e.g. "e4".
+func ParseSquare(s string) (Square, error) {
+	if len(s) != 2 || s[0] < 'a' || s[0] > 'h' || s[1] < '1' || s[1] > '8' {
+		return NoSquare, fmt.Errorf("invalid square %q", s)
+	}
+	return NewSquare(int(s[0]-'a'), int(s[1]-'1')), nil
+}
+
+// Bitboard is a set of squares, one bit per square.
+type Bitboard uint64
+
+// SquareBB returns the bitboard of sq alone.
+func SquareBB(sq Square) Bitboard {
+	return 1 << sq
+}
+
+// Has returns true if sq is in the set.
+func (b Bitboard) Has(sq Square) bool {
+	return b&SquareBB(sq) != 0
+}
+
+// Count returns the number of squares in the set.
+func (b Bitboard) Count() int {
+	return bits.OnesCount64(uint64(b))
+}
+
+// LSB returns the lowest square in the set, which must not be empty.
+func (b Bitboard) LSB() Square {
+	return Square(bits.TrailingZeros64(uint64(b)))
+}
+
+// Pop removes and returns the lowest square in the set, which must not be
+// empty.
+func (b *Bitboard) Pop() Square {
+	sq := b.LSB()
+	*b &= *b - 1
+	return sq
+}
+
+// CastlingRights are the castling moves still allowed by the rules.
+type CastlingRights uint8
+
+const (
+	WhiteKingSide CastlingRights = 1 << iota
+	WhiteQueenSide
+	BlackKingSide
+	BlackQueenSide
+
+	NoCastling CastlingRights = 0
+)
+
+func (rights CastlingRights) String() string {
+	if rights == NoCastling {
+		return "-"
+	}
+	s := ""
+	for i, c := range "KQkq" {
+		if rights&(1<<uint(i)) != 0 {
+			s += string(c)
+		}
+	}
+	return s
+}
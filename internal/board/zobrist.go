@@ -0,0 +1,29 @@
+package board
+
+// zobristSeed is fixed, so keys are the same in every run.
+const zobristSeed = 1070372
+
+// Zobrist keys for each piece on each square, combination of castling rights,
+// en passant file and the side to move.
+var (
+	zobristPieces    [nPieces][nSquares]uint64
+	zobristCastling  [16]uint64
+	zobristEnPassant [8]uint64
+	zobristBlack     uint64
+)
+
+func init() {
+	rng := xorshift(zobristSeed)
+	for p := WhiteKing; p <= BlackPawn; p++ {
+		for sq := range zobristPieces[p] {
+			zobristPieces[p][sq] = rng.next()
+		}
+	}
+	for rights := range zobristCastling[1:] {
+		zobristCastling[rights+1] = rng.next()
+	}
+	for file := range zobristEnPassant {
+		zobristEnPassant[file] = rng.next()
+	}
+	zobristBlack = rng.next()
+}
@@ -3,13 +3,21 @@ package handlerfakes
 
 import (
 	"sync"
+	"time"
 
+	"github.com/mhv2109/uci-impl/internal/board"
 	"github.com/mhv2109/uci-impl/internal/handler"
 	"github.com/mhv2109/uci-impl/internal/handler/info"
 	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
 )
 
 type FakeEmitter struct {
+	EmitBenchStub        func(solver.BenchResult)
+	emitBenchMutex       sync.RWMutex
+	emitBenchArgsForCall []struct {
+		arg1 solver.BenchResult
+	}
 	EmitBestmoveStub        func(...string)
 	emitBestmoveMutex       sync.RWMutex
 	emitBestmoveArgsForCall []struct {
@@ -27,6 +35,11 @@ type FakeEmitter struct {
 	emitCopyProtectionOkMutex       sync.RWMutex
 	emitCopyProtectionOkArgsForCall []struct {
 	}
+	EmitEvalStub        func(utils.Evaluation)
+	emitEvalMutex       sync.RWMutex
+	emitEvalArgsForCall []struct {
+		arg1 utils.Evaluation
+	}
 	EmitIDStub        func()
 	emitIDMutex       sync.RWMutex
 	emitIDArgsForCall []struct {
@@ -41,6 +54,12 @@ type FakeEmitter struct {
 	emitOptionArgsForCall []struct {
 		arg1 solver.Solver
 	}
+	EmitPerftStub        func([]board.PerftResult, time.Duration)
+	emitPerftMutex       sync.RWMutex
+	emitPerftArgsForCall []struct {
+		arg1 []board.PerftResult
+		arg2 time.Duration
+	}
 	EmitReadyOKStub        func()
 	emitReadyOKMutex       sync.RWMutex
 	emitReadyOKArgsForCall []struct {
@@ -65,6 +84,37 @@ type FakeEmitter struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeEmitter) EmitBench(arg1 solver.BenchResult) {
+	fake.emitBenchMutex.Lock()
+	fake.emitBenchArgsForCall = append(fake.emitBenchArgsForCall, struct {
+		arg1 solver.BenchResult
+	}{arg1})
+	fake.recordInvocation("EmitBench", []interface{}{arg1})
+	fake.emitBenchMutex.Unlock()
+	if fake.EmitBenchStub != nil {
+		fake.EmitBenchStub(arg1)
+	}
+}
+
+func (fake *FakeEmitter) EmitBenchCallCount() int {
+	fake.emitBenchMutex.RLock()
+	defer fake.emitBenchMutex.RUnlock()
+	return len(fake.emitBenchArgsForCall)
+}
+
+func (fake *FakeEmitter) EmitBenchCalls(stub func(solver.BenchResult)) {
+	fake.emitBenchMutex.Lock()
+	defer fake.emitBenchMutex.Unlock()
+	fake.EmitBenchStub = stub
+}
+
+func (fake *FakeEmitter) EmitBenchArgsForCall(i int) solver.BenchResult {
+	fake.emitBenchMutex.RLock()
+	defer fake.emitBenchMutex.RUnlock()
+	argsForCall := fake.emitBenchArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeEmitter) EmitBestmove(arg1 ...string) {
 	fake.emitBestmoveMutex.Lock()
 	fake.emitBestmoveArgsForCall = append(fake.emitBestmoveArgsForCall, struct {
@@ -165,6 +215,37 @@ func (fake *FakeEmitter) EmitCopyProtectionOkCalls(stub func()) {
 	fake.EmitCopyProtectionOkStub = stub
 }
 
+func (fake *FakeEmitter) EmitEval(arg1 utils.Evaluation) {
+	fake.emitEvalMutex.Lock()
+	fake.emitEvalArgsForCall = append(fake.emitEvalArgsForCall, struct {
+		arg1 utils.Evaluation
+	}{arg1})
+	fake.recordInvocation("EmitEval", []interface{}{arg1})
+	fake.emitEvalMutex.Unlock()
+	if fake.EmitEvalStub != nil {
+		fake.EmitEvalStub(arg1)
+	}
+}
+
+func (fake *FakeEmitter) EmitEvalCallCount() int {
+	fake.emitEvalMutex.RLock()
+	defer fake.emitEvalMutex.RUnlock()
+	return len(fake.emitEvalArgsForCall)
+}
+
+func (fake *FakeEmitter) EmitEvalCalls(stub func(utils.Evaluation)) {
+	fake.emitEvalMutex.Lock()
+	defer fake.emitEvalMutex.Unlock()
+	fake.EmitEvalStub = stub
+}
+
+func (fake *FakeEmitter) EmitEvalArgsForCall(i int) utils.Evaluation {
+	fake.emitEvalMutex.RLock()
+	defer fake.emitEvalMutex.RUnlock()
+	argsForCall := fake.emitEvalArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeEmitter) EmitID() {
 	fake.emitIDMutex.Lock()
 	fake.emitIDArgsForCall = append(fake.emitIDArgsForCall, struct {
@@ -250,6 +331,43 @@ func (fake *FakeEmitter) EmitOptionArgsForCall(i int) solver.Solver {
 	return argsForCall.arg1
 }
 
+func (fake *FakeEmitter) EmitPerft(arg1 []board.PerftResult, arg2 time.Duration) {
+	var arg1Copy []board.PerftResult
+	if arg1 != nil {
+		arg1Copy = make([]board.PerftResult, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.emitPerftMutex.Lock()
+	fake.emitPerftArgsForCall = append(fake.emitPerftArgsForCall, struct {
+		arg1 []board.PerftResult
+		arg2 time.Duration
+	}{arg1Copy, arg2})
+	fake.recordInvocation("EmitPerft", []interface{}{arg1Copy, arg2})
+	fake.emitPerftMutex.Unlock()
+	if fake.EmitPerftStub != nil {
+		fake.EmitPerftStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeEmitter) EmitPerftCallCount() int {
+	fake.emitPerftMutex.RLock()
+	defer fake.emitPerftMutex.RUnlock()
+	return len(fake.emitPerftArgsForCall)
+}
+
+func (fake *FakeEmitter) EmitPerftCalls(stub func([]board.PerftResult, time.Duration)) {
+	fake.emitPerftMutex.Lock()
+	defer fake.emitPerftMutex.Unlock()
+	fake.EmitPerftStub = stub
+}
+
+func (fake *FakeEmitter) EmitPerftArgsForCall(i int) ([]board.PerftResult, time.Duration) {
+	fake.emitPerftMutex.RLock()
+	defer fake.emitPerftMutex.RUnlock()
+	argsForCall := fake.emitPerftArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeEmitter) EmitReadyOK() {
 	fake.emitReadyOKMutex.Lock()
 	fake.emitReadyOKArgsForCall = append(fake.emitReadyOKArgsForCall, struct {
@@ -368,6 +486,8 @@ func (fake *FakeEmitter) EmitUCIOKCalls(stub func()) {
 func (fake *FakeEmitter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.emitBenchMutex.RLock()
+	defer fake.emitBenchMutex.RUnlock()
 	fake.emitBestmoveMutex.RLock()
 	defer fake.emitBestmoveMutex.RUnlock()
 	fake.emitCopyProtectionCheckingMutex.RLock()
@@ -376,12 +496,16 @@ func (fake *FakeEmitter) Invocations() map[string][][]interface{} {
 	defer fake.emitCopyProtectionErrorMutex.RUnlock()
 	fake.emitCopyProtectionOkMutex.RLock()
 	defer fake.emitCopyProtectionOkMutex.RUnlock()
+	fake.emitEvalMutex.RLock()
+	defer fake.emitEvalMutex.RUnlock()
 	fake.emitIDMutex.RLock()
 	defer fake.emitIDMutex.RUnlock()
 	fake.emitInfoMutex.RLock()
 	defer fake.emitInfoMutex.RUnlock()
 	fake.emitOptionMutex.RLock()
 	defer fake.emitOptionMutex.RUnlock()
+	fake.emitPerftMutex.RLock()
+	defer fake.emitPerftMutex.RUnlock()
 	fake.emitReadyOKMutex.RLock()
 	defer fake.emitReadyOKMutex.RUnlock()
 	fake.emitRegistrationCheckingMutex.RLock()
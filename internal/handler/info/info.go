@@ -11,6 +11,7 @@ type Info struct {
 	// selective search depth in plies,
 	// if the engine sends seldepth there must also be a "depth" present in the same string.
 	seldepth       *int
+	multipv        *int     // the k-th best line, if the engine sends more than one, the best is 1.
 	time           *int     // the time searched in ms, this should be sent together with the pv.
 	nodes          *int     // x nodes searched, the engine should send this info regularly
 	currmovenumber *int     // currently searching move number x, for the first move x should be 1 not 0.
@@ -45,6 +46,10 @@ func (i *Info) SetSeldepth(seldepth int) {
 	i.seldepth = &seldepth
 }
 
+func (i *Info) SetMultipv(multipv int) {
+	i.multipv = &multipv
+}
+
 func (i *Info) SetTime(time int) {
 	i.time = &time
 }
@@ -86,7 +91,34 @@ func (i *Info) AddPv(p string) {
 }
 
 func (i *Info) SetScore(scoretype ScoreType, value int) {
-	i.score = newScore(scoretype, value)
+	i.score = newScore(scoretype, value, Exact)
+}
+
+// SetBoundedScore sets a score that is only a lower or upper bound, e.g.
+// while a search is repeated with a wider window.
+func (i *Info) SetBoundedScore(scoretype ScoreType, value int, bound Bound) {
+	i.score = newScore(scoretype, value, bound)
+}
+
+// Pv returns the best line, if any.
+func (i *Info) Pv() []string {
+	return i.pv
+}
+
+// Score returns the score and its type, and false if no score was set.
+func (i *Info) Score() (ScoreType, int, bool) {
+	if i.score == nil {
+		return "", 0, false
+	}
+	return i.score.scoretype, i.score.value, true
+}
+
+// ScoreBound returns whether the score is exact or a bound.
+func (i *Info) ScoreBound() Bound {
+	if i.score == nil {
+		return Exact
+	}
+	return i.score.bound
 }
 
 func (i *Info) SetCurrmove(currmove string) {
@@ -115,6 +147,9 @@ func (i *Info) String() string {
 	if i.seldepth != nil {
 		builder.WriteString(fmt.Sprintf(" seldepth %d", *i.seldepth))
 	}
+	if i.multipv != nil {
+		builder.WriteString(fmt.Sprintf(" multipv %d", *i.multipv))
+	}
 	if i.time != nil {
 		builder.WriteString(fmt.Sprintf(" time %d", *i.time))
 	}
@@ -141,13 +176,6 @@ func (i *Info) String() string {
 		builder.WriteString(fmt.Sprintf(" cpuload %d", *i.cpuload))
 	}
 
-	if len(i.pv) > 0 {
-		builder.WriteString(" pv")
-		for _, p := range i.pv {
-			builder.WriteString(fmt.Sprintf(" %s", p))
-		}
-	}
-
 	if i.score != nil {
 		builder.WriteString(fmt.Sprintf(" %s", i.score))
 	}
@@ -156,6 +184,15 @@ func (i *Info) String() string {
 		builder.WriteString(fmt.Sprintf(" currmove %s", *i.currmove))
 	}
 
+	// GUIs read the moves of pv up to the end of the line, so it comes last;
+	// refutation and currline are sent in info lines of their own
+	if len(i.pv) > 0 {
+		builder.WriteString(" pv")
+		for _, p := range i.pv {
+			builder.WriteString(fmt.Sprintf(" %s", p))
+		}
+	}
+
 	if len(i.refutation) > 0 {
 		builder.WriteString(" refutation")
 		for _, r := range i.refutation {
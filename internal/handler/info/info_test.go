@@ -30,6 +30,11 @@ var _ = Describe("Info", func() {
 			testUint("seldepth", 99, info)
 		})
 
+		It("multipv", func() {
+			info.SetMultipv(3)
+			testUint("multipv", 3, info)
+		})
+
 		It("time", func() {
 			info.SetTime(99)
 			testUint("time", 99, info)
@@ -79,11 +84,13 @@ var _ = Describe("Info", func() {
 		a, e := info.String(), "info pv e4 Nf3 Bb5"
 		Expect(a).
 			To(Equal(e))
+		Expect(info.Pv()).
+			To(Equal([]string{"e4", "Nf3", "Bb5"}))
 	})
 
 	Describe("score", func() {
 		var st ScoreType
-		sts := []ScoreType{CP, Mate, Lowerbound, Upperbound}
+		sts := []ScoreType{CP, Mate}
 		i := 0
 
 		BeforeEach(func() {
@@ -100,6 +107,51 @@ var _ = Describe("Info", func() {
 			Expect(a).
 				To(Equal(e))
 		})
+
+		It("Score is returned", func() {
+			scoretype, value, ok := info.Score()
+			Expect(ok).
+				To(BeTrue())
+			Expect(scoretype).
+				To(Equal(st))
+			Expect(value).
+				To(Equal(i))
+			Expect(info.ScoreBound()).
+				To(Equal(Exact))
+		})
+	})
+
+	Describe("bounded score", func() {
+		It("Serializes the bound after the value", func() {
+			info.SetBoundedScore(CP, 25, Lowerbound)
+			Expect(info.String()).
+				To(Equal("info score cp 25 lowerbound"))
+
+			info.SetBoundedScore(Mate, -3, Upperbound)
+			Expect(info.String()).
+				To(Equal("info score mate -3 upperbound"))
+		})
+
+		It("Returns the bound", func() {
+			info.SetBoundedScore(CP, 25, Upperbound)
+			Expect(info.ScoreBound()).
+				To(Equal(Upperbound))
+
+			info.SetScore(CP, 25)
+			Expect(info.ScoreBound()).
+				To(Equal(Exact))
+		})
+	})
+
+	It("Writes the principal variation after the score", func() {
+		info.SetDepth(2)
+		info.AddPv("e2e4")
+		info.AddPv("e7e5")
+		info.SetScore(CP, 25)
+
+		a, e := info.String(), "info depth 2 score cp 25 pv e2e4 e7e5"
+		Expect(a).
+			To(Equal(e))
 	})
 
 	It("currmove", func() {
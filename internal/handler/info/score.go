@@ -6,22 +6,33 @@ type ScoreType string
 
 // ScoreType constants identify the scoring method.
 const (
-	CP         ScoreType = "cp"         // the score from the engine's point of view in centipawns.
-	Mate       ScoreType = "mate"       // mate in y moves, not plies. If the engine is getting mated use negative values for y.
-	Lowerbound ScoreType = "lowerbound" // the score is just a lower bound.
-	Upperbound ScoreType = "upperbound" // the score is just an upper bound.
+	CP   ScoreType = "cp"   // the score from the engine's point of view in centipawns.
+	Mate ScoreType = "mate" // mate in y moves, not plies. If the engine is getting mated use negative values for y.
+)
+
+type Bound string
+
+// Bound constants tell whether the score is exact, or only a bound of it.
+const (
+	Exact      Bound = ""           // the score is exact.
+	Lowerbound Bound = "lowerbound" // the score is just a lower bound.
+	Upperbound Bound = "upperbound" // the score is just an upper bound.
 )
 
 // score struct contains scoring metadata to share with the GUI.
 type score struct {
 	scoretype ScoreType // what the value signifies
 	value     int       // score value
+	bound     Bound     // whether value is exact or a bound
 }
 
-func newScore(scoretype ScoreType, value int) *score {
-	return &score{scoretype, value}
+func newScore(scoretype ScoreType, value int, bound Bound) *score {
+	return &score{scoretype, value, bound}
 }
 
 func (s *score) String() string {
+	if s.bound != Exact {
+		return fmt.Sprintf("score %s %d %s", s.scoretype, s.value, s.bound)
+	}
 	return fmt.Sprintf("score %s %d", s.scoretype, s.value)
 }
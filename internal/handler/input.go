@@ -1,9 +1,14 @@
+// Package handler speaks UCI with the GUI: it parses the commands read from
+// stdin, passes them on to a Solver, and prints its replies.  Besides the
+// standard commands, it accepts the eval, perft and bench debugging commands,
+// which are non-standard, like Stockfish's.
 package handler
 
 import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mhv2109/uci-impl/internal/config"
 	"github.com/mhv2109/uci-impl/internal/solver"
@@ -61,6 +66,12 @@ func (handler *UCIInputHandler) Handle(input []string) {
 		handler.handlePonderHit(input)
 	case "quit":
 		handler.handleQuit(input)
+	case "eval":
+		handler.handleEval(input)
+	case "perft":
+		handler.handlePerft(input)
+	case "bench":
+		handler.handleBench(input)
 	default:
 		// invalid input, do nothing and return (TODO: setup logger)
 	}
@@ -202,7 +213,7 @@ func (handler *UCIInputHandler) handleRegister(input []string) {
 // As the engine's reaction to "ucinewgame" can take some time the GUI should always send "isready"
 // after "ucinewgame" to wait for the engine to finish its operation.
 func (handler *UCIInputHandler) handleUcinewgame(input []string) {
-	// we don't currently take any specific action upon a new game
+	handler.solver.NewGame()
 }
 
 // position [fen <fenstring> | startpos ]  moves <move1> .... <movei>
@@ -287,6 +298,11 @@ func (handler *UCIInputHandler) handlePosition(input []string) {
 // * infinite
 // 	search until the "stop" command. Do not exit the search without being told so in this mode!
 func (handler *UCIInputHandler) handleGo(input []string) {
+	if len(input) > 1 && input[1] == "perft" {
+		handler.handlePerft(input[1:])
+		return
+	}
+
 	sp := solver.NewSearchParams()
 
 	var searchmoves []string
@@ -324,6 +340,10 @@ func (handler *UCIInputHandler) handleGo(input []string) {
 			if depth, err := strconv.Atoi(input[i+1]); err == nil {
 				sp.Depth = depth
 			}
+		case "nodes":
+			if nodes, err := strconv.Atoi(input[i+1]); err == nil {
+				sp.Nodes = nodes
+			}
 		case "mate":
 			if mate, err := strconv.Atoi(input[i+1]); err == nil {
 				sp.Mate = mate
@@ -372,3 +392,49 @@ func (handler *UCIInputHandler) handlePonderHit(input []string) {
 func (handler *UCIInputHandler) handleQuit(input []string) {
 	os.Exit(0)
 }
+
+// eval
+// Print the static evaluation of the current position, broken down by term for
+// each side.
+func (handler *UCIInputHandler) handleEval(input []string) {
+	handler.emitter.EmitEval(handler.solver.Eval())
+}
+
+// perft <x>
+// Also accepted as "go perft <x>".  Count the leaf nodes of the move tree of the
+// current position to depth x, printing the count below each move, to check the
+// move generator and measure its speed.
+func (handler *UCIInputHandler) handlePerft(input []string) {
+	if len(input) != 2 {
+		// invalid input, do nothing and return (TODO: setup logger)
+		return
+	}
+
+	depth, err := strconv.Atoi(input[1])
+	if err != nil || depth < 1 {
+		// invalid argument, do nothing and return (TODO: setup logger)
+		return
+	}
+
+	start := time.Now()
+	results := handler.solver.Perft(depth)
+	handler.emitter.EmitPerft(results, time.Since(start))
+}
+
+// bench [ <x> ]
+// Search a fixed set of positions to depth x, or a default depth, and print the
+// nodes searched and the nodes per second.  The node count is reproducible, so
+// it detects unintended changes to the search.
+func (handler *UCIInputHandler) handleBench(input []string) {
+	depth := 0
+	if len(input) > 1 {
+		d, err := strconv.Atoi(input[1])
+		if err != nil || d < 1 {
+			// invalid argument, do nothing and return (TODO: setup logger)
+			return
+		}
+		depth = d
+	}
+
+	handler.emitter.EmitBench(handler.solver.Bench(depth))
+}
@@ -4,10 +4,12 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/mhv2109/uci-impl/internal/board"
 	. "github.com/mhv2109/uci-impl/internal/handler"
 	hf "github.com/mhv2109/uci-impl/internal/handler/handlerfakes"
 	s "github.com/mhv2109/uci-impl/internal/solver"
 	sf "github.com/mhv2109/uci-impl/internal/solver/solverfakes"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
 )
 
 var _ = Describe("Handler", func() {
@@ -38,6 +40,88 @@ var _ = Describe("Handler", func() {
 		for i := 0; i < 100; i++ {
 			handler.Handle(input)
 		}
+
+		Expect(solver.NewGameCallCount()).To(Equal(100))
+	})
+
+	It("eval", func() {
+		var evaluation utils.Evaluation
+		evaluation.Phase = 12
+		solver.EvalReturns(evaluation)
+
+		handler.Handle([]string{"eval"})
+
+		Expect(solver.EvalCallCount()).
+			To(Equal(1))
+		Expect(emitter.EmitEvalCallCount()).
+			To(Equal(1))
+		Expect(emitter.EmitEvalArgsForCall(0)).
+			To(Equal(evaluation))
+	})
+
+	var _ = Describe("perft", func() {
+		It("Counts the moves to depth", func() {
+			results := board.NewPosition().Divide(1)
+			solver.PerftReturns(results)
+
+			handler.Handle([]string{"perft", "3"})
+
+			Expect(solver.PerftArgsForCall(0)).
+				To(Equal(3))
+			Expect(emitter.EmitPerftCallCount()).
+				To(Equal(1))
+			emitted, _ := emitter.EmitPerftArgsForCall(0)
+			Expect(emitted).
+				To(Equal(results))
+		})
+
+		It("Is accepted as a go command", func() {
+			handler.Handle([]string{"go", "perft", "2"})
+
+			Expect(solver.PerftArgsForCall(0)).
+				To(Equal(2))
+			Expect(solver.StartSearchCallCount()).
+				To(BeZero())
+		})
+
+		It("Ignores an invalid depth", func() {
+			handler.Handle([]string{"perft"})
+			handler.Handle([]string{"perft", "x"})
+			handler.Handle([]string{"perft", "0"})
+
+			Expect(solver.PerftCallCount()).
+				To(BeZero())
+			Expect(emitter.EmitPerftCallCount()).
+				To(BeZero())
+		})
+	})
+
+	var _ = Describe("bench", func() {
+		It("Searches to the default depth", func() {
+			result := s.BenchResult{Positions: 2, Nodes: 1000}
+			solver.BenchReturns(result)
+
+			handler.Handle([]string{"bench"})
+
+			Expect(solver.BenchArgsForCall(0)).
+				To(BeZero())
+			Expect(emitter.EmitBenchArgsForCall(0)).
+				To(Equal(result))
+		})
+
+		It("Searches to the given depth", func() {
+			handler.Handle([]string{"bench", "4"})
+
+			Expect(solver.BenchArgsForCall(0)).
+				To(Equal(4))
+		})
+
+		It("Ignores an invalid depth", func() {
+			handler.Handle([]string{"bench", "x"})
+
+			Expect(solver.BenchCallCount()).
+				To(BeZero())
+		})
 	})
 
 	var _ = Describe("setoption", func() {
@@ -126,8 +210,8 @@ var _ = Describe("Handler", func() {
 
 		It("search params", func() {
 			input := []string{"go", "ponder", "wtime", "1", "btime", "2", "winc",
-				"3", "binc", "4", "movestogo", "5", "depth", "6", "mate", "7",
-				"movetime", "8", "infinite"}
+				"3", "binc", "4", "movestogo", "5", "depth", "6", "nodes", "9",
+				"mate", "7", "movetime", "8", "infinite"}
 			expected := s.NewSearchParams()
 			expected.Ponder = true
 			expected.Wtime = 1
@@ -136,6 +220,7 @@ var _ = Describe("Handler", func() {
 			expected.Binc = 4
 			expected.Movestogo = 5
 			expected.Depth = 6
+			expected.Nodes = 9
 			expected.Mate = 7
 			expected.Movetime = 8
 			expected.Infinite = true
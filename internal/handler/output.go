@@ -3,9 +3,12 @@ package handler
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/mhv2109/uci-impl/internal/board"
 	"github.com/mhv2109/uci-impl/internal/handler/info"
 	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . Emitter
@@ -26,6 +29,9 @@ type Emitter interface {
 	EmitRegistrationError()
 	EmitInfo(i info.Info)
 	EmitOption(s solver.Solver)
+	EmitEval(e utils.Evaluation)
+	EmitPerft(results []board.PerftResult, elapsed time.Duration)
+	EmitBench(result solver.BenchResult)
 }
 
 type emitterImpl struct{}
@@ -391,3 +397,40 @@ func (e *emitterImpl) EmitOption(s solver.Solver) {
 		fmt.Println(o)
 	}
 }
+
+// eval
+// Prints the static evaluation of the current position broken down by term for
+// each side, to help diagnose the evaluation.
+func (e *emitterImpl) EmitEval(evaluation utils.Evaluation) {
+	fmt.Println(evaluation.String())
+}
+
+// perft
+// Prints the number of leaf nodes below each root move, then the total with the
+// time taken and nodes per second.
+func (e *emitterImpl) EmitPerft(results []board.PerftResult, elapsed time.Duration) {
+	total := 0
+	for _, r := range results {
+		fmt.Printf("%s: %d\n", r.Move, r.Nodes)
+		total += r.Nodes
+	}
+
+	ms := elapsed.Milliseconds()
+	nps := int64(0)
+	if elapsed > 0 {
+		nps = int64(float64(total) / elapsed.Seconds())
+	}
+	fmt.Printf("\nNodes searched: %d\nTime: %d ms\nNodes/second: %d\n", total, ms, nps)
+}
+
+// bench
+// Prints the number of positions and nodes searched by the benchmark, with the
+// time taken and nodes per second.
+func (e *emitterImpl) EmitBench(result solver.BenchResult) {
+	nps := int64(0)
+	if result.Elapsed > 0 {
+		nps = int64(float64(result.Nodes) / result.Elapsed.Seconds())
+	}
+	fmt.Printf("\nPositions: %d\nTotal time (ms): %d\nNodes searched: %d\nNodes/second: %d\n",
+		result.Positions, result.Elapsed.Milliseconds(), result.Nodes, nps)
+}
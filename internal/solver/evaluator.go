@@ -0,0 +1,97 @@
+package solver
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+// EvaluatorOptionName is the name of the Option that selects the Evaluator.
+const EvaluatorOptionName = "Evaluator"
+
+// Names of the built-in Evaluators.
+const (
+	MaterialEvaluatorName  = "material"
+	ClassicalEvaluatorName = "classical"
+
+	DefaultEvaluatorName = ClassicalEvaluatorName
+)
+
+// Evaluator statically evaluates positions.  Evaluators may cache results, so
+// each goroutine must use its own.
+type Evaluator interface {
+	Evaluate(position *board.Position) utils.CentiPawns // from White's point of view
+	Trace(position *board.Position) utils.Evaluation    // the evaluation broken down by term
+}
+
+// IncrementalEvaluator is an Evaluator that keeps the evaluation of the
+// position searched up to date as moves are made and unmade, rather than
+// evaluating each position from scratch.
+type IncrementalEvaluator interface {
+	Evaluator
+	Reset(position *board.Position)                     // start from position
+	MakeMove(position *board.Position, move board.Move) // move is about to be made in position
+	UnmakeMove()                                        // undo the last MakeMove
+	Current() utils.CentiPawns                          // evaluate the current position, from White's point of view
+}
+
+var (
+	evaluatorsMutex sync.RWMutex
+	evaluatorNames  []string // in order of registration
+	evaluators      = make(map[string]func() Evaluator)
+)
+
+// RegisterEvaluator makes an Evaluator available as name, ignoring case.
+// newEvaluator is called to create an Evaluator for each search.
+func RegisterEvaluator(name string, newEvaluator func() Evaluator) {
+	evaluatorsMutex.Lock()
+	defer evaluatorsMutex.Unlock()
+
+	key := strings.ToLower(name)
+	if _, ok := evaluators[key]; !ok {
+		evaluatorNames = append(evaluatorNames, name)
+	}
+	evaluators[key] = newEvaluator
+}
+
+// EvaluatorNames returns the names of all registered Evaluators.
+func EvaluatorNames() []string {
+	evaluatorsMutex.RLock()
+	defer evaluatorsMutex.RUnlock()
+
+	return append([]string{}, evaluatorNames...)
+}
+
+// NewEvaluator returns a new Evaluator registered as name, or the default
+// Evaluator if there is none.
+func NewEvaluator(name string) Evaluator {
+	evaluatorsMutex.RLock()
+	newEvaluator, ok := evaluators[strings.ToLower(name)]
+	if !ok {
+		newEvaluator = evaluators[DefaultEvaluatorName]
+	}
+	evaluatorsMutex.RUnlock()
+
+	return newEvaluator()
+}
+
+// NewEvaluatorOption returns the Option that selects one of the registered
+// Evaluators.
+func NewEvaluatorOption() *Option {
+	return &Option{
+		Name:    EvaluatorOptionName,
+		Type:    OptionComboType,
+		Default: DefaultEvaluatorName,
+		Vars:    EvaluatorNames()}
+}
+
+func init() {
+	RegisterEvaluator(MaterialEvaluatorName, func() Evaluator {
+		return utils.MaterialEvaluator{}
+	})
+	RegisterEvaluator(ClassicalEvaluatorName, func() Evaluator {
+		return utils.NewEvaluator()
+	})
+}
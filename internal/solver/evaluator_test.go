@@ -0,0 +1,88 @@
+package solver_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	. "github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+type constantEvaluator utils.CentiPawns
+
+func (e constantEvaluator) Evaluate(position *board.Position) utils.CentiPawns {
+	return utils.CentiPawns(e)
+}
+
+func (e constantEvaluator) Trace(position *board.Position) utils.Evaluation {
+	return utils.Evaluation{}
+}
+
+var _ = Describe("Evaluator", func() {
+	var position *board.Position
+
+	BeforeEach(func() {
+		position, _ = board.ParseFEN("4k3/8/8/8/8/8/8/N3K3 w - - 0 1")
+	})
+
+	It("Registers the built-in evaluators", func() {
+		Expect(EvaluatorNames()).
+			To(ContainElement(MaterialEvaluatorName))
+		Expect(EvaluatorNames()).
+			To(ContainElement(ClassicalEvaluatorName))
+	})
+
+	It("Looks up evaluators ignoring case", func() {
+		Expect(NewEvaluator("Material").Evaluate(position)).
+			To(Equal(utils.KnightValue))
+		Expect(NewEvaluator("classical").Evaluate(position)).
+			ToNot(Equal(utils.KnightValue))
+	})
+
+	It("Falls back to the default evaluator", func() {
+		Expect(NewEvaluator("no such evaluator").Evaluate(position)).
+			To(Equal(NewEvaluator(DefaultEvaluatorName).Evaluate(position)))
+	})
+
+	It("Accepts new evaluators", func() {
+		RegisterEvaluator("constant", func() Evaluator {
+			return constantEvaluator(42)
+		})
+		Expect(NewEvaluator("constant").Evaluate(position)).
+			To(BeEquivalentTo(42))
+		Expect(NewEvaluatorOption().Vars).
+			To(ContainElement("constant"))
+	})
+
+	It("Is selected with a combo option", func() {
+		option := NewEvaluatorOption()
+		Expect(option.Type).
+			To(Equal(OptionComboType))
+		Expect(option.Default).
+			To(Equal(DefaultEvaluatorName))
+	})
+
+	It("Converts the game to a position with its moves made", func() {
+		s := NewAbstractSolver(NewOptions())
+		s.SetStartPosition("e2e4", "e7e5", "g1f3")
+
+		position := s.Position()
+		Expect(position.String()).
+			To(Equal(s.Game.Position().String()))
+		Expect(position.Ply()).
+			To(Equal(3))
+	})
+
+	It("Evaluates the current position of a solver", func() {
+		s := NewAbstractSolver(NewOptions())
+		s.SetPosition("4k3/8/8/8/8/8/8/N3K3 w - - 0 1")
+
+		s.SetOption(EvaluatorOptionName, MaterialEvaluatorName)
+		e := s.Eval()
+		Expect(e.Total()).
+			To(Equal(utils.KnightValue))
+		Expect(e.Term(utils.PositionTerm, board.White)).
+			To(Equal(utils.Score{}))
+	})
+})
@@ -0,0 +1,59 @@
+package minimax
+
+import (
+	"math"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+const (
+	// aspirationMinDepth is the first iteration searched with an aspiration
+	// window, as the scores of shallower ones still swing too much.
+	aspirationMinDepth = 4
+	// aspirationDelta is how far the first aspiration window reaches on
+	// either side of the previous score.
+	aspirationDelta utils.CentiPawns = 25
+	// aspirationMaxDelta is how far a window may reach before it's opened
+	// completely on the side that failed.
+	aspirationMaxDelta utils.CentiPawns = 1000
+)
+
+// aspiration searches position to depth with a window centred on previous, the
+// score of the last iteration, and returns the score of the search.  The
+// score rarely changes much between iterations, and the narrow window cuts off
+// more of the tree.  A search that fails low or high is reported with its
+// bound and repeated with a window twice as wide on the side that failed, and
+// best, the best move of the last iteration, is reported for a fail low.
+func (minimax *minimaxAlgo) aspiration(position *board.Position, depth int,
+	previous utils.CentiPawns, best board.Move, moves ...board.Move) utils.CentiPawns {
+
+	alpha, beta := utils.CentiPawns(-math.MaxInt64), utils.CentiPawns(math.MaxInt64)
+	delta := aspirationDelta
+	if depth >= aspirationMinDepth && !utils.IsMate(previous) {
+		alpha, beta = previous-delta, previous+delta
+	}
+
+	for {
+		minimax.rootMove = board.NoMove
+		score := minimax.search(position, depth, 0, alpha, beta, moves...)
+		if minimax.Stopped() || score > alpha && score < beta {
+			return score
+		}
+
+		delta *= 2
+		if score <= alpha {
+			minimax.executeBestMoveCallbacks(best, depth, score, alpha, beta)
+			alpha = score - delta
+			if delta > aspirationMaxDelta || utils.IsMate(score) {
+				alpha = -math.MaxInt64
+			}
+		} else {
+			minimax.executeBestMoveCallbacks(minimax.rootMove, depth, score, alpha, beta)
+			beta = score + delta
+			if delta > aspirationMaxDelta || utils.IsMate(score) {
+				beta = math.MaxInt64
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package minimax
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	hf "github.com/mhv2109/uci-impl/internal/handler/handlerfakes"
+	"github.com/mhv2109/uci-impl/internal/handler/info"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+var _ = Describe("Aspiration windows", func() {
+	var (
+		algo      *minimaxAlgo
+		emitter   *hf.FakeEmitter
+		submitted [][]string
+	)
+
+	BeforeEach(func() {
+		submitted = nil
+		emitter = &hf.FakeEmitter{}
+		algo = newTestAlgo(5, emitter, &submitted)
+	})
+
+	// bounds returns the bounds of the scores reported so far.
+	bounds := func() []info.Bound {
+		var bounds []info.Bound
+		for j := 0; j < emitter.EmitInfoCallCount(); j++ {
+			i := emitter.EmitInfoArgsForCall(j)
+			bounds = append(bounds, i.ScoreBound())
+		}
+		return bounds
+	}
+
+	It("Finds the score of a full window search", func() {
+		position := parse(middlegame)
+		algo.prepare(position)
+		full := algo.search(position, 4, 0, -math.MaxInt64, math.MaxInt64)
+
+		for _, previous := range []utils.CentiPawns{full, full - 300, full + 300, utils.MateIn(3)} {
+			algo.Clear()
+			algo.prepare(position)
+			Expect(algo.aspiration(position, 4, previous, board.NoMove)).
+				To(Equal(full), "previous %d", previous)
+		}
+	})
+
+	It("Reports an upper bound when failing low", func() {
+		position := parse(board.StartFEN)
+		best := position.LegalMoves(nil)[0]
+		algo.prepare(position)
+		algo.aspiration(position, 4, 500, best)
+
+		Expect(bounds()).
+			To(ContainElement(info.Upperbound))
+		Expect(bounds()).
+			ToNot(ContainElement(info.Lowerbound))
+		i := emitter.EmitInfoArgsForCall(0)
+		Expect(i.String()).
+			To(MatchRegexp(`score cp -?\d+ upperbound pv %s$`, best))
+	})
+
+	It("Reports a lower bound when failing high", func() {
+		position := parse(board.StartFEN)
+		algo.prepare(position)
+		algo.aspiration(position, 4, -500, board.NoMove)
+
+		Expect(bounds()).
+			To(ContainElement(info.Lowerbound))
+		Expect(bounds()).
+			ToNot(ContainElement(info.Upperbound))
+	})
+
+	It("Isn't used by shallow iterations", func() {
+		position := parse(board.StartFEN)
+		algo.prepare(position)
+		algo.aspiration(position, aspirationMinDepth-1, 500, board.NoMove)
+
+		Expect(emitter.EmitInfoCallCount()).
+			To(BeZero())
+	})
+
+	It("Reports an exact score for the completed iteration", func() {
+		algo.Start(parse(middlegame))
+
+		bounds := bounds()
+		Expect(bounds[len(bounds)-1]).
+			To(Equal(info.Exact))
+	})
+})
@@ -0,0 +1,70 @@
+package minimax
+
+import (
+	"log"
+	"time"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver"
+)
+
+const (
+	// BenchDepth is the depth the bench positions are searched to by default.
+	BenchDepth = 6
+	// BenchHash is the transposition table size in MB of the bench searches.
+	BenchHash = 16
+)
+
+// benchPositions are searched by Bench.  Changing them changes the node
+// count, so only do so deliberately.
+var benchPositions = []string{
+	board.StartFEN,
+	"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10",
+	"rnbqkb1r/pp1p1ppp/4pn2/2p5/2PP4/2N5/PP2PPPP/R1BQKBNR w KQkq - 0 4",
+	"r1bq1rk1/pp2bppp/2n1pn2/3p4/2PP4/2N1PN2/PP1B1PPP/R2QKB1R w KQ - 0 8",
+	"2r3k1/pp3ppp/4p3/3pP3/3P4/P3QP2/1q4PP/2R3K1 b - - 0 25",
+	"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+	"8/8/4k3/3p4/3P4/4K3/8/8 w - - 0 1",
+	"6k1/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1",
+}
+
+// Bench searches each of the bench positions to depth, or BenchDepth if depth
+// is 0, from a cleared transposition table of BenchHash MB, with the
+// evaluation and search techniques set by the options.  The search is
+// deterministic, so the total node count is a fingerprint of the search that
+// changes only when the search or evaluation does.
+func (solver *MinimaxSolver) Bench(depth int) solver.BenchResult {
+	solver.StopSearch()
+	solver.searching.Wait()
+
+	if depth < 1 {
+		depth = BenchDepth
+	}
+	solver.setParams()
+
+	algo := newMinimaxAlgo(depth, BenchHash, func([]string) bool { return true }, solver.emitter)
+	algo.SetEvaluator(solver.getEvaluator(), solver.useEvalFile())
+	solver.setTechniques(algo)
+
+	return bench(algo)
+}
+
+// bench searches the bench positions with algo.
+func bench(algo *minimaxAlgo) solver.BenchResult {
+	result := solver.BenchResult{Positions: len(benchPositions)}
+	start := time.Now()
+	for _, fen := range benchPositions {
+		position, err := board.ParseFEN(fen)
+		if err != nil {
+			log.Panicln(err)
+		}
+
+		algo.Clear()
+		algo.Start(position)
+		result.Nodes += algo.totalNodes()
+	}
+	result.Elapsed = time.Since(start)
+
+	return result
+}
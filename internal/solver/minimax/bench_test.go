@@ -0,0 +1,41 @@
+package minimax
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	hf "github.com/mhv2109/uci-impl/internal/handler/handlerfakes"
+)
+
+var _ = Describe("Bench", func() {
+	It("Has valid positions", func() {
+		for _, fen := range benchPositions {
+			_, err := board.ParseFEN(fen)
+			Expect(err).
+				ToNot(HaveOccurred(), fen)
+		}
+	})
+
+	It("Searches the same nodes each time", func() {
+		solver := NewMinimaxSolverWithEmitter(&hf.FakeEmitter{})
+		first := solver.Bench(2)
+		Expect(first.Positions).
+			To(Equal(len(benchPositions)))
+		Expect(first.Nodes).
+			To(BeNumerically(">", 0))
+
+		// neither the Hash option nor the position change the result
+		solver.SetOption("Hash", "1")
+		solver.SetStartPosition("e2e4")
+		second := solver.Bench(2)
+		Expect(second.Nodes).
+			To(Equal(first.Nodes))
+	})
+
+	It("Searches deeper for more nodes", func() {
+		solver := NewMinimaxSolverWithEmitter(&hf.FakeEmitter{})
+		Expect(solver.Bench(2).Nodes).
+			To(BeNumerically(">", solver.Bench(1).Nodes))
+	})
+})
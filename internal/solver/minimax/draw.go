@@ -0,0 +1,17 @@
+package minimax
+
+import "github.com/mhv2109/uci-impl/internal/board"
+
+// fiftyMoveLimit is the number of half moves without a capture or pawn move
+// after which the game is drawn.
+const fiftyMoveLimit = 100
+
+// isDraw returns true if position is drawn by the fifty-move rule or by
+// repetition, see board.Position.IsRepetition.  Checkmate on the last move
+// takes precedence over the fifty-move rule.
+func (minimax *minimaxAlgo) isDraw(position *board.Position) bool {
+	if position.HalfMoveClock() >= fiftyMoveLimit {
+		return !position.InCheck() || position.HasLegalMoves()
+	}
+	return position.IsRepetition(minimax.root)
+}
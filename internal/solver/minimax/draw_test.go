@@ -0,0 +1,52 @@
+package minimax
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+var _ = Describe("Draws", func() {
+	var minimax *minimaxAlgo
+
+	BeforeEach(func() {
+		minimax = newMinimaxAlgo(1, 1, nil, nil)
+	})
+
+	play := func(position *board.Position, moves ...string) *board.Position {
+		for _, lan := range moves {
+			move, err := position.ParseMove(lan)
+			Expect(err).
+				ToNot(HaveOccurred())
+			position.MakeMove(move)
+		}
+		return position
+	}
+
+	It("Scores a repetition within the search as a draw", func() {
+		position := board.NewPosition()
+		minimax.root = position.Ply()
+		Expect(minimax.isDraw(play(position, "g1f3", "g8f6", "f3g1", "f6g8"))).
+			To(BeFalse())
+		Expect(minimax.isDraw(play(position, "g1f3"))).
+			To(BeTrue())
+	})
+
+	It("Scores the fifty-move rule as a draw", func() {
+		position, _ := board.ParseFEN("7k/8/8/8/8/8/P7/K5Q1 w - - 99 80")
+		Expect(minimax.isDraw(position)).
+			To(BeFalse())
+		Expect(minimax.isDraw(play(position, "g1g2"))).
+			To(BeTrue())
+		position.UnmakeMove()
+		Expect(minimax.isDraw(play(position, "a2a3"))).
+			To(BeFalse())
+	})
+
+	It("Prefers checkmate to the fifty-move rule", func() {
+		position, _ := board.ParseFEN("7k/8/6K1/8/8/8/8/R7 w - - 99 80")
+		Expect(minimax.isDraw(play(position, "a1a8"))).
+			To(BeFalse())
+	})
+})
@@ -1,28 +1,69 @@
 package minimax
 
 import (
-	"math"
 	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/mhv2109/uci-impl/internal/board"
 	"github.com/mhv2109/uci-impl/internal/handler"
 	"github.com/mhv2109/uci-impl/internal/handler/info"
+	"github.com/mhv2109/uci-impl/internal/solver"
 	"github.com/mhv2109/uci-impl/internal/solver/utils"
-	"github.com/notnil/chess"
 )
 
 type submitCallback func([]string) bool
-type searchCallback func(*chess.Position, ...*chess.Move)
-type moveCallback func(*chess.Move, int, utils.CentiPawns, utils.CentiPawns, utils.CentiPawns)
+type searchCallback func(*board.Position, ...board.Move)
+type moveCallback func(board.Move, int, utils.CentiPawns, utils.CentiPawns, utils.CentiPawns)
 
-type minimaxAlgo struct {
-	MaxDepth int
-	HashSize int
+// MaxPly is the deepest iteration the search will attempt.
+const MaxPly = 64
 
-	player  chess.Color
+type minimaxAlgo struct {
+	MaxDepth  int
+	HashSize  int
+	Randomize bool // break ties in move ordering at random
+	MaxNodes  int  // stop after this many nodes if > 0
+	MateMoves int  // stop once a mate in this many moves is found if > 0
+	Contempt  int  // centipawns the searching player gives up to avoid a draw
+	MultiPV   int  // number of best root moves searched and reported, see multipv.go
+	// search later moves with a null window, see search
+	PVS bool
+	// prune nodes where passing the move still fails high, see pruning.go
+	NullMove bool
+	// search late quiet moves to a reduced depth, see pruning.go
+	LateMoveReductions bool
+	// search a ply deeper when in check
+	CheckExtensions bool
+	// report the line searched by each thread about once a second
+	ShowCurrLine bool
+
+	id      int            // thread number, 0 for the main thread
+	main    *minimaxAlgo   // main thread of a helper thread, see smp.go
+	helpers []*minimaxAlgo // helper threads of the main thread
+	player  board.Color
+	root    int // ply of the searched position, see board.Position.IsRepetition
 	submit  submitCallback
 	emitter handler.Emitter
 
-	cache *cacheWrapper
+	tt            *transpositionTable
+	orderer       *moveOrderer
+	evaluator     solver.Evaluator
+	evaluatorName string
+	incremental   solver.IncrementalEvaluator // evaluator, if it's incremental
+	timeManager   *solver.TimeManager         // optional, limits the search by time
+
+	stopped   int32      // set atomically by Stop
+	completed int        // depth of the last completed iteration
+	rootMove  board.Move // best root move of the current iteration
+	line      int        // index of the principal variation being searched
+	seldepth  int        // deepest ply reached in the current iteration
+	nodes     int64      // nodes searched since the search started, set atomically
+	startTime time.Time
+	lastLine  time.Time // when the current line was last reported
+	pv        pvTable
+	moves     [MaxPly + 1][]board.Move // move lists, reused at each ply
 
 	searchStartedCallbacks  []searchCallback
 	currentMoveCallbacks    []moveCallback
@@ -33,26 +74,35 @@ type minimaxAlgo struct {
 func newMinimaxAlgo(maxDepth int, hashSize int, submit func([]string) bool,
 	emitter handler.Emitter) *minimaxAlgo {
 
+	return newSearchThread(maxDepth, hashSize, newTranspositionTable(hashSize), submit, emitter)
+}
+
+// newSearchThread returns a minimaxAlgo using the transposition table tt, which
+// may be shared with other threads.
+func newSearchThread(maxDepth int, hashSize int, tt *transpositionTable,
+	submit func([]string) bool, emitter handler.Emitter) *minimaxAlgo {
+
 	// maxDepth must be >= 1
 	if maxDepth < 1 {
 		maxDepth = 1
 	}
 
-	// Benchmarks calculated about 1021 entries per MB
-	cache := newCacheWrapper(hashSize * 1021)
-
 	minimax := &minimaxAlgo{
-		maxDepth,
-		hashSize,
-		chess.NoColor,
-		submit,
-		emitter,
-		cache,
-		make([]searchCallback, 0),
-		make([]moveCallback, 0, 1),
-		make([]moveCallback, 0, 1),
-		make([]searchCallback, 0)}
-
+		MaxDepth:             maxDepth,
+		HashSize:             hashSize,
+		MultiPV:              1,
+		PVS:                  true,
+		NullMove:             true,
+		LateMoveReductions:   true,
+		CheckExtensions:      true,
+		submit:               submit,
+		emitter:              emitter,
+		tt:                   tt,
+		orderer:              newMoveOrderer(),
+		currentMoveCallbacks: make([]moveCallback, 0, 1),
+		bestMoveCallbacks:    make([]moveCallback, 0, 1)}
+
+	minimax.SetEvaluator(solver.DefaultEvaluatorName, false)
 	minimax.Init()
 
 	return minimax
@@ -79,120 +129,348 @@ func (minimax *minimaxAlgo) AddSearchFinishedCallback(callback searchCallback) {
 	minimax.searchFinishedCallbacks = append(minimax.searchFinishedCallbacks, callback)
 }
 
-func (minimax *minimaxAlgo) executeSearchStartedCallbacks(position *chess.Position, moves ...*chess.Move) {
+func (minimax *minimaxAlgo) executeSearchStartedCallbacks(position *board.Position, moves ...board.Move) {
 	executeSearchCallbacks(position, moves, minimax.searchStartedCallbacks)
 }
 
-func (minimax *minimaxAlgo) executeCurrentMoveCallbacks(move *chess.Move,
+func (minimax *minimaxAlgo) executeCurrentMoveCallbacks(move board.Move,
 	depth int, score, alpha, beta utils.CentiPawns) {
 	executeMoveCallbacks(move, depth, score, alpha, beta, minimax.currentMoveCallbacks)
 }
 
-func (minimax *minimaxAlgo) executeBestMoveCallbacks(move *chess.Move,
+func (minimax *minimaxAlgo) executeBestMoveCallbacks(move board.Move,
 	depth int, score, alpha, beta utils.CentiPawns) {
 	executeMoveCallbacks(move, depth, score, alpha, beta, minimax.bestMoveCallbacks)
 }
 
-func (minimax *minimaxAlgo) executeSearchFinishedCallbacks(position *chess.Position, moves ...*chess.Move) {
+func (minimax *minimaxAlgo) executeSearchFinishedCallbacks(position *board.Position, moves ...board.Move) {
 	executeSearchCallbacks(position, moves, minimax.searchFinishedCallbacks)
 }
 
-func executeMoveCallbacks(move *chess.Move, depth int, score,
+func executeMoveCallbacks(move board.Move, depth int, score,
 	alpha, beta utils.CentiPawns, callbacks []moveCallback) {
 	for _, callback := range callbacks {
 		callback(move, depth, score, alpha, beta)
 	}
 }
 
-func executeSearchCallbacks(position *chess.Position, moves []*chess.Move, callbacks []searchCallback) {
+func executeSearchCallbacks(position *board.Position, moves []board.Move, callbacks []searchCallback) {
 	for _, callback := range callbacks {
 		callback(position, moves...)
 	}
 }
 
-func (minimax *minimaxAlgo) Start(position *chess.Position, moves ...*chess.Move) {
-	minimax.player = position.Turn()
+// Start runs an iterative deepening search from position, searching to depth
+// 1, 2, 3... until MaxDepth is reached, the node limit is hit, a mate within
+// MateMoves is found or Stop is called.  The best move of each iteration whose
+// first principal variation completed is submitted; a partially searched one
+// is discarded.  The moves made to reach position are used to detect
+// repetitions, and moves are made and unmade in it while searching.
+func (minimax *minimaxAlgo) Start(position *board.Position, moves ...board.Move) {
+	minimax.tt.NewSearch()
+	minimax.prepare(position)
 	minimax.executeSearchStartedCallbacks(position, moves...)
-	minimax.maxStep(position, 0, -math.MaxInt64, math.MaxInt64, moves...)
-}
-
-func (minimax *minimaxAlgo) maxStep(state *chess.Position, depth int,
-	alpha, beta utils.CentiPawns, moves ...*chess.Move) utils.CentiPawns {
 
-	var bestMove *chess.Move
+	bestMove := board.NoMove
+	hasMoves := len(moves) > 0 || position.HasLegalMoves()
+	helpers := minimax.startHelpers(position, hasMoves, moves...)
+	var lines []rootLine
+	for depth := 1; depth <= minimax.MaxDepth && hasMoves; depth++ {
+		minimax.seldepth = 0
+		found := minimax.searchLines(position, depth, lines, moves...)
+		if len(found) == 0 {
+			break
+		}
+		lines = found
 
-	if gameWon(state) || depth >= minimax.MaxDepth {
-		alpha = minimax.score(state)
-	} else {
-		if validMoves := getMoves(state, moves...); len(validMoves) == 0 {
-			alpha = minimax.score(state)
+		// submit the best move and the expected reply to ponder on
+		bestMove = lines[0].move
+		if pv := lines[0].pv; len(pv) > 1 && pv[0] == bestMove.String() {
+			minimax.submit(pv[:2])
 		} else {
-			for _, move := range validMoves {
-				nextState := state.Update(move)
-
-				var score utils.CentiPawns
-
-				nextStateString := nextState.String()
-				if value, ok := minimax.cache.Get(nextStateString, depth); ok {
-					score = value
-				} else {
-					score = minimax.minStep(nextState, depth, alpha, beta)
-					minimax.cache.Add(nextStateString, depth, score)
-				}
-
-				if score > alpha {
-					alpha = score
-					if depth <= 0 {
-						minimax.submit([]string{move.String()})
-						minimax.executeBestMoveCallbacks(move, depth, score, alpha, beta)
-						bestMove = move
-					}
-				}
-				minimax.executeCurrentMoveCallbacks(move, depth, score, alpha, beta)
-				if alpha >= beta {
-					break
-				}
+			minimax.submit([]string{bestMove.String()})
+		}
+		minimax.completed = depth
+
+		if minimax.Stopped() {
+			break
+		}
+
+		if score := lines[0].score; minimax.MateMoves > 0 && utils.IsMate(score) {
+			if moves := utils.MateDistance(score); moves > 0 && moves <= minimax.MateMoves {
+				break
 			}
 		}
+
+		// don't start an iteration that is unlikely to finish in time
+		if minimax.timeManager != nil && minimax.timeManager.SoftExpired() {
+			break
+		}
 	}
 
-	if depth == 0 {
-		minimax.executeSearchFinishedCallbacks(state, bestMove)
+	minimax.stopHelpers(helpers)
+	minimax.executeSearchFinishedCallbacks(position, bestMove)
+}
+
+// prepare resets the thread for a search of position.
+func (minimax *minimaxAlgo) prepare(position *board.Position) {
+	minimax.player = position.Turn()
+	minimax.root = position.Ply()
+	minimax.startTime = time.Now()
+	minimax.lastLine = minimax.startTime
+	atomic.StoreInt64(&minimax.nodes, 0)
+	minimax.completed = 0
+
+	if minimax.incremental != nil {
+		minimax.incremental.Reset(position)
 	}
 
-	return alpha
+	minimax.orderer.NewSearch()
+}
+
+// SetEvaluator switches to the Evaluator registered as name, unless it's
+// already in use and reload is false.
+func (minimax *minimaxAlgo) SetEvaluator(name string, reload bool) {
+	if reload || !strings.EqualFold(name, minimax.evaluatorName) {
+		minimax.evaluator = solver.NewEvaluator(name)
+		minimax.evaluatorName = name
+		minimax.incremental, _ = minimax.evaluator.(solver.IncrementalEvaluator)
+	}
+	for _, helper := range minimax.helpers {
+		helper.SetEvaluator(name, reload)
+	}
 }
 
-func (minimax *minimaxAlgo) minStep(state *chess.Position, depth int,
-	alpha, beta utils.CentiPawns, moves ...*chess.Move) utils.CentiPawns {
+// Reset clears a previous Stop so the next search can run.
+func (minimax *minimaxAlgo) Reset() {
+	atomic.StoreInt32(&minimax.stopped, 0)
+}
 
-	if gameWon(state) || depth >= minimax.MaxDepth {
-		beta = minimax.score(state)
-	} else {
-		if validMoves := getMoves(state, moves...); len(validMoves) == 0 {
-			beta = minimax.score(state)
+// Clear empties the transposition table and move ordering tables, e.g. before
+// a new game.
+func (minimax *minimaxAlgo) Clear() {
+	minimax.tt.Clear()
+	minimax.orderer.Clear()
+	for _, helper := range minimax.helpers {
+		helper.orderer.Clear()
+	}
+}
+
+// Stop signals a running search to return as soon as possible.
+func (minimax *minimaxAlgo) Stop() {
+	atomic.StoreInt32(&minimax.stopped, 1)
+}
+
+// Stopped returns true if Stop has been called since the search started, on
+// this thread or its main thread.
+func (minimax *minimaxAlgo) Stopped() bool {
+	return atomic.LoadInt32(&minimax.stopped) != 0 ||
+		minimax.main != nil && minimax.main.Stopped()
+}
+
+// search is a negamax principal variation search of position to depth, and
+// returns its score from the point of view of the side to move, within alpha
+// and beta.  The first move of a node is searched with the full window, and
+// later moves with a null window around alpha, to prove cheaply that they're
+// worse; one that isn't is searched again with the full window.  Null move
+// pruning, late move reductions and check extensions narrow the search further,
+// see pruning.go.
+func (minimax *minimaxAlgo) search(position *board.Position, depth, ply int,
+	alpha, beta utils.CentiPawns, moves ...board.Move) utils.CentiPawns {
+
+	minimax.pv.Clear(ply)
+
+	if minimax.Stopped() {
+		return alpha
+	}
+
+	minimax.visit(position, ply)
+
+	if ply > 0 && minimax.isDraw(position) {
+		return minimax.relative(position, minimax.drawScore())
+	}
+
+	inCheck := position.InCheck()
+	if inCheck && minimax.CheckExtensions {
+		depth++
+	}
+	if depth <= 0 {
+		return minimax.quiesce(position, ply, alpha, beta)
+	} else if ply >= MaxPly {
+		return minimax.score(position, ply, true)
+	}
+
+	score, hashMove, ok := minimax.probe(position, depth, ply, alpha, beta)
+	if ok {
+		return score
+	}
+
+	pvNode := beta-alpha > 1
+	if !pvNode && !inCheck && minimax.nullMoveAllowed(position, depth, ply, beta) {
+		if score := minimax.nullMoveSearch(position, depth, ply, beta); score >= beta {
+			return beta
+		}
+	}
+
+	validMoves := minimax.getMoves(position, ply, hashMove, moves...)
+	if len(validMoves) == 0 {
+		return minimax.score(position, ply, false)
+	}
+
+	alphaOrig := alpha
+	bestMove := board.NoMove
+	for i, move := range validMoves {
+		minimax.play(position, move)
+		if i == 0 {
+			score = -minimax.search(position, depth-1, ply+1, -beta, -alpha)
 		} else {
-			for _, move := range validMoves {
-				nextState := state.Update(move)
-
-				score := minimax.maxStep(nextState, depth+1, alpha, beta)
-
-				if score < beta {
-					// dont submit opponent's moves!
-					beta = score
-				}
-				minimax.executeCurrentMoveCallbacks(move, depth, score, alpha, beta)
-				if alpha >= beta {
-					break
-				}
+			reduction := minimax.reduction(position, depth, i, inCheck, move)
+			lower := -beta
+			if minimax.PVS {
+				lower = -alpha - 1
 			}
+			score = -minimax.search(position, depth-1-reduction, ply+1, lower, -alpha)
+			if score > alpha && reduction > 0 {
+				score = -minimax.search(position, depth-1, ply+1, lower, -alpha)
+			}
+			if score > alpha && score < beta && lower != -beta {
+				score = -minimax.search(position, depth-1, ply+1, -beta, -alpha)
+			}
+		}
+		minimax.undo(position)
+		if minimax.Stopped() {
+			return alpha
+		}
+
+		if score > alpha {
+			alpha = score
+			bestMove = move
+			minimax.pv.Update(ply, move)
+		}
+
+		minimax.executeCurrentMoveCallbacks(move, ply, score, alpha, beta)
+
+		if alpha >= beta {
+			minimax.orderer.Update(position, ply, depth, move)
+			break
 		}
 	}
 
-	return beta
+	if ply == 0 {
+		minimax.rootMove = bestMove
+		if minimax.line > 0 {
+			// the best root moves are excluded, so the score isn't the root's
+			return alpha
+		}
+	}
+
+	b := boundExact
+	if alpha <= alphaOrig {
+		b = boundUpper
+	} else if alpha >= beta {
+		b = boundLower
+	}
+	minimax.store(position, depth, ply, alpha, b, bestMove)
+
+	return alpha
+}
+
+// play makes move in position, keeping an incremental evaluator in step.
+// undo must be called once the resulting position has been searched.
+func (minimax *minimaxAlgo) play(position *board.Position, move board.Move) {
+	if minimax.incremental != nil {
+		minimax.incremental.MakeMove(position, move)
+	}
+	position.MakeMove(move)
+}
+
+// undo unmakes the last move played in position.
+func (minimax *minimaxAlgo) undo(position *board.Position) {
+	position.UnmakeMove()
+	if minimax.incremental != nil {
+		minimax.incremental.UnmakeMove()
+	}
+}
+
+// visit counts a node searched at ply, and stops the search once MaxNodes is
+// reached by all threads.  The first iteration always completes, so there is a
+// move to play.
+func (minimax *minimaxAlgo) visit(position *board.Position, ply int) {
+	nodes := atomic.AddInt64(&minimax.nodes, 1)
+	minimax.updateSeldepth(ply)
+
+	if minimax.MaxNodes > 0 && minimax.totalNodes() >= minimax.MaxNodes && minimax.completed > 0 {
+		minimax.Stop()
+	}
+	if minimax.ShowCurrLine && nodes%currLineNodes == 0 {
+		minimax.infoCurrLine(position)
+	}
+}
+
+// probe looks up position in the transposition table, and returns a score if
+// the stored result is deep enough to decide the node within the alpha-beta
+// window.  The stored best move is returned in any case, to be searched first.
+// The root is always searched, so a best move is found.
+func (minimax *minimaxAlgo) probe(position *board.Position, depth, ply int,
+	alpha, beta utils.CentiPawns) (utils.CentiPawns, ttMove, bool) {
+
+	entry, ok := minimax.tt.Probe(position.Key())
+	if !ok {
+		return 0, noTTMove, false
+	}
+	if ply == 0 || int(entry.depth) < depth {
+		return 0, entry.move, false
+	}
+
+	score, b := fromTT(position, utils.CentiPawns(entry.score), entry.bound, ply)
+	switch {
+	case b == boundExact && score <= alpha, b == boundUpper && score <= alpha:
+		return alpha, entry.move, true
+	case b == boundExact && score >= beta, b == boundLower && score >= beta:
+		return beta, entry.move, true
+	case b == boundExact:
+		return score, entry.move, true
+	}
+	return 0, entry.move, false
+}
+
+func (minimax *minimaxAlgo) store(position *board.Position, depth, ply int, score utils.CentiPawns,
+	b bound, move board.Move) {
+
+	score, b = toTT(position, score, b, ply)
+	minimax.tt.Store(position.Key(), depth, score, b, move)
+}
+
+// toTT converts a score and bound at ply relative to the side to move in
+// position to White's point of view, with mate scores relative to the node, as
+// stored in the transposition table.
+func toTT(position *board.Position, score utils.CentiPawns, b bound, ply int) (utils.CentiPawns, bound) {
+	score = utils.MateToTT(score, ply)
+	if position.Turn() != board.Black {
+		return score, b
+	}
+	return -score, flipBound(b)
+}
+
+// fromTT is the inverse of toTT.
+func fromTT(position *board.Position, score utils.CentiPawns, b bound, ply int) (utils.CentiPawns, bound) {
+	if position.Turn() == board.Black {
+		score, b = -score, flipBound(b)
+	}
+	return utils.MateFromTT(score, ply), b
+}
+
+func flipBound(b bound) bound {
+	switch b {
+	case boundLower:
+		return boundUpper
+	case boundUpper:
+		return boundLower
+	}
+	return b
 }
 
-func (minimax *minimaxAlgo) infoCurrentMove(move *chess.Move, depth int, score, alpha, beta utils.CentiPawns) {
+func (minimax *minimaxAlgo) infoCurrentMove(move board.Move, depth int, score, alpha, beta utils.CentiPawns) {
 	i := info.Info{}
 	i.SetDepth(depth)
 	i.SetCurrmove(move.String())
@@ -200,58 +478,137 @@ func (minimax *minimaxAlgo) infoCurrentMove(move *chess.Move, depth int, score,
 	minimax.emitter.EmitInfo(i)
 }
 
-func (minimax *minimaxAlgo) infoBestMove(move *chess.Move, depth int, score, alpha, beta utils.CentiPawns) {
+func (minimax *minimaxAlgo) infoBestMove(move board.Move, depth int, score, alpha, beta utils.CentiPawns) {
 	i := info.Info{}
-	i.SetPv([]string{move.String()})
-	i.SetScore(info.CP, int(score))
+	i.SetDepth(depth)
+	i.SetSeldepth(minimax.seldepth)
+	if minimax.MultiPV > 1 {
+		i.SetMultipv(minimax.line + 1)
+	}
+	elapsed := time.Since(minimax.startTime)
+	i.SetTime(int(elapsed / time.Millisecond))
+	nodes := minimax.totalNodes()
+	i.SetNodes(nodes)
+	i.SetNps(nps(nodes, elapsed))
+	i.SetHashfull(minimax.tt.Hashfull())
+	if line := minimax.pv.Line(); len(line) > 0 && line[0] == move.String() {
+		i.SetPv(line)
+	} else if move != board.NoMove {
+		i.SetPv([]string{move.String()})
+	}
+	bound := info.Exact
+	if score <= alpha {
+		bound = info.Upperbound
+	} else if score >= beta {
+		bound = info.Lowerbound
+	}
+	setScore(&i, score, bound)
 	minimax.emitter.EmitInfo(i)
 }
 
-func (minimax *minimaxAlgo) score(state *chess.Position) utils.CentiPawns {
-	if winner, ok := getWinner(state); ok {
-		// return winning/losing score
-		if winner == minimax.player {
-			return utils.MaxScore
-		}
-		return -utils.MaxScore
-	} else if state.Status() == chess.NoMethod {
-		// return normal score
-		board := state.Board()
-		if minimax.player == chess.White {
-			return utils.WhiteAdvantage(board)
-		}
-		return utils.BlackAdvantage(board)
+// currLineNodes is how often, in nodes, a thread checks whether to report its
+// current line.
+const currLineNodes = 1 << 12
+
+// currLineInterval is the least time between two reports of the current line
+// of a thread.
+var currLineInterval = time.Second
+
+// infoCurrLine reports the line from the root to position searched by the
+// thread, at most once every currLineInterval.
+func (minimax *minimaxAlgo) infoCurrLine(position *board.Position) {
+	now := time.Now()
+	if now.Sub(minimax.lastLine) < currLineInterval {
+		return
+	}
+	minimax.lastLine = now
+
+	moves := position.MovesSince(minimax.root)
+	line := make([]string, len(moves))
+	for j, move := range moves {
+		line[j] = move.String()
 	}
-	// return draw score
-	return 0
+
+	i := info.Info{}
+	i.SetCurrline(minimax.id+1, line...)
+	minimax.emitter.EmitInfo(i)
 }
 
-func getWinner(state *chess.Position) (chess.Color, bool) {
-	if !gameWon(state) {
-		return chess.NoColor, false
+// setScore reports score in centipawns, or mate scores in moves to mate.
+func setScore(i *info.Info, score utils.CentiPawns, bound info.Bound) {
+	if utils.IsMate(score) {
+		i.SetBoundedScore(info.Mate, utils.MateDistance(score), bound)
+	} else {
+		i.SetBoundedScore(info.CP, int(score), bound)
 	}
-	if turn := state.Turn(); turn == chess.Black {
-		return chess.White, true
+}
+
+func nps(nodes int, elapsed time.Duration) int {
+	if elapsed <= 0 {
+		return 0
 	}
-	return chess.Black, true
+	return int(int64(nodes) * int64(time.Second) / int64(elapsed))
 }
 
-func gameWon(state *chess.Position) bool {
-	outcome := state.Status()
-	if outcome == chess.Checkmate {
-		return true
+// score evaluates position at ply from the point of view of the side to move.
+// A position without legal moves is checkmate or stalemate.
+func (minimax *minimaxAlgo) score(position *board.Position, ply int, hasMoves bool) utils.CentiPawns {
+	if !hasMoves {
+		if !position.InCheck() {
+			return minimax.relative(position, minimax.drawScore())
+		}
+		// return losing score, preferring the shortest mate
+		return utils.MatedIn(ply)
+	}
+
+	var score utils.CentiPawns
+	if minimax.incremental != nil {
+		score = minimax.incremental.Current()
+	} else {
+		score = minimax.evaluator.Evaluate(position)
+	}
+	if position.Turn() == board.Black {
+		return -score
 	}
-	return false
+	return score
 }
 
-func getMoves(state *chess.Position, moves ...*chess.Move) []*chess.Move {
-	if len(moves) > 0 {
-		return randomize(moves)
+// relative converts score from the searching player's point of view to that of
+// the side to move in position.
+func (minimax *minimaxAlgo) relative(position *board.Position, score utils.CentiPawns) utils.CentiPawns {
+	if position.Turn() != minimax.player {
+		return -score
+	}
+	return score
+}
+
+// drawScore is the score of a draw from the searching player's point of view,
+// which is negative if the player prefers to play on.
+func (minimax *minimaxAlgo) drawScore() utils.CentiPawns {
+	return utils.CentiPawns(-minimax.Contempt)
+}
+
+// getMoves returns the moves to search in position at ply, or the given moves
+// if any, in the order to search them.
+func (minimax *minimaxAlgo) getMoves(position *board.Position, ply int,
+	hashMove ttMove, moves ...board.Move) []board.Move {
+
+	if len(moves) == 0 {
+		moves = position.LegalMoves(minimax.moves[ply][:0])
+	} else {
+		moves = append(minimax.moves[ply][:0], moves...)
+	}
+	minimax.moves[ply] = moves
+
+	if minimax.Randomize {
+		// shuffled before the stable sort, so equally scored moves are tried
+		// in random order
+		moves = randomize(moves)
 	}
-	return randomize(state.ValidMoves())
+	return minimax.orderer.Order(position, ply, hashMove, moves)
 }
 
-func randomize(moves []*chess.Move) []*chess.Move {
+func randomize(moves []board.Move) []board.Move {
 	for i := range moves {
 		j := rand.Intn(i + 1)
 		moves[i], moves[j] = moves[j], moves[i]
@@ -1,13 +1,37 @@
-package minimax_test
+package minimax
 
 import (
 	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	hf "github.com/mhv2109/uci-impl/internal/handler/handlerfakes"
 )
 
 func TestMinimax(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Minimax Suite")
 }
+
+// middlegame is a quiet middlegame position, deep enough to search for the
+// search techniques to make a difference.
+const middlegame = "r1bq1rk1/pp2bppp/2n1pn2/3p4/2PP4/2N1PN2/PP1B1PPP/R2QKB1R w KQ - 0 8"
+
+// parse returns the position of fen, which must be valid.
+func parse(fen string) *board.Position {
+	position, err := board.ParseFEN(fen)
+	Expect(err).
+		ToNot(HaveOccurred())
+	return position
+}
+
+// newTestAlgo returns a search to maxDepth with a small transposition table,
+// reporting to emitter and appending the moves it submits to submitted.
+func newTestAlgo(maxDepth int, emitter *hf.FakeEmitter, submitted *[][]string) *minimaxAlgo {
+	return newMinimaxAlgo(maxDepth, 16, func(move []string) bool {
+		*submitted = append(*submitted, move)
+		return true
+	}, emitter)
+}
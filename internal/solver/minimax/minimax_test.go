@@ -1,15 +1,22 @@
 package minimax
 
 import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/notnil/chess"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/mhv2109/uci-impl/internal/board"
 	"github.com/mhv2109/uci-impl/internal/handler"
 	hf "github.com/mhv2109/uci-impl/internal/handler/handlerfakes"
 	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/nn"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
 )
 
 var _ = Describe("MinimaxSolver", func() {
@@ -23,6 +30,11 @@ var _ = Describe("MinimaxSolver", func() {
 		minimaxSolver = NewMinimaxSolverWithEmitter(emitter)
 	})
 
+	AfterEach(func() {
+		// waits for a search still running, so it doesn't outlive the spec
+		minimaxSolver.NewGame()
+	})
+
 	It("Returns results", func() {
 		sp := solver.NewSearchParams()
 		sp.Wtime = 300000
@@ -32,6 +44,261 @@ var _ = Describe("MinimaxSolver", func() {
 		Eventually(ch).
 			Should(Receive())
 	})
+
+	It("Searches until stopped in infinite mode", func() {
+		sp := solver.NewSearchParams()
+		sp.Infinite = true
+
+		ch := minimaxSolver.StartSearch(sp)
+		Eventually(ch).
+			Should(Receive())
+		Consistently(ch).
+			ShouldNot(BeClosed())
+
+		minimaxSolver.StopSearch()
+		Eventually(ch).
+			Should(BeClosed())
+	})
+
+	It("Stops after movetime", func() {
+		sp := solver.NewSearchParams()
+		sp.Movetime = 100
+
+		ch := minimaxSolver.StartSearch(sp)
+		Eventually(ch, "1s").
+			Should(BeClosed())
+	})
+
+	It("Keeps searching after ponderhit until the time is up", func() {
+		sp := solver.NewSearchParams()
+		sp.Ponder = true
+		sp.Movetime = 200
+
+		ch := minimaxSolver.StartSearch(sp)
+		Consistently(ch, "300ms").
+			ShouldNot(Receive())
+
+		minimaxSolver.PonderHit()
+
+		var result []string
+		Eventually(ch, "1s").
+			Should(Receive(&result))
+		Eventually(ch, "1s").
+			Should(BeClosed())
+		Expect(result).
+			ToNot(BeEmpty())
+	})
+
+	It("Answers ponderhit after more iterations than legal moves", func() {
+		minimaxSolver.SetPosition("7k/8/8/8/8/8/8/K6R b - - 0 1")
+		sp := solver.NewSearchParams()
+		sp.Ponder = true
+
+		ch := minimaxSolver.StartSearch(sp)
+		Eventually(func() int {
+			return emitter.(*hf.FakeEmitter).EmitInfoCallCount()
+		}, "5s").
+			Should(BeNumerically(">", 4))
+
+		done := make(chan struct{})
+		go func() {
+			minimaxSolver.PonderHit()
+			close(done)
+		}()
+		Eventually(done, "1s").
+			Should(BeClosed())
+		Eventually(ch, "1s").
+			Should(Receive())
+		Eventually(ch, "1s").
+			Should(BeClosed())
+	})
+
+	It("Keeps searching after ponderhit until the depth is reached", func() {
+		sp := solver.NewSearchParams()
+		sp.Ponder = true
+		sp.Depth = 5
+
+		var results [][]string
+		done := make(chan struct{})
+		go func() {
+			for result := range minimaxSolver.StartSearch(sp) {
+				results = append(results, result)
+			}
+			close(done)
+		}()
+		Eventually(func() int {
+			return emitter.(*hf.FakeEmitter).EmitInfoCallCount()
+		}, "5s").
+			Should(BeNumerically(">", 0))
+
+		minimaxSolver.PonderHit()
+		Eventually(done, "10s").
+			Should(BeClosed())
+		Expect(results).
+			ToNot(BeEmpty())
+
+		i := emitter.(*hf.FakeEmitter).EmitInfoArgsForCall(emitter.(*hf.FakeEmitter).EmitInfoCallCount() - 1)
+		Expect(i.String()).
+			To(HavePrefix("info depth 5 "))
+	})
+
+	It("Stops at go depth", func() {
+		sp := solver.NewSearchParams()
+		sp.Depth = 2
+
+		var results [][]string
+		for result := range minimaxSolver.StartSearch(sp) {
+			results = append(results, result)
+		}
+		Expect(results).
+			To(HaveLen(2))
+	})
+
+	It("Stops after go nodes with the same result each time", func() {
+		search := func() []string {
+			minimaxSolver := NewMinimaxSolverWithEmitter(&hf.FakeEmitter{})
+			sp := solver.NewSearchParams()
+			sp.Nodes = 2000
+
+			var result []string
+			for result = range minimaxSolver.StartSearch(sp) {
+			}
+			return result
+		}
+
+		first := search()
+		Expect(first).
+			ToNot(BeEmpty())
+		Expect(search()).
+			To(Equal(first))
+	})
+
+	It("Publishes evaluation parameters as spin options", func() {
+		var names []string
+		for _, option := range minimaxSolver.GetOptions() {
+			if option.Type == solver.OptionSpinType {
+				names = append(names, option.Name)
+			}
+		}
+		Expect(names).
+			To(ContainElement("Pawn Value"))
+		Expect(names).
+			To(ContainElement("Knight Mobility MG"))
+	})
+
+	It("Applies evaluation parameters on the next search", func() {
+		defer utils.ResetParams()
+
+		minimaxSolver.SetOption("pawn value", "120")
+		Expect(utils.PawnValue).
+			To(BeEquivalentTo(100))
+
+		sp := solver.NewSearchParams()
+		sp.Depth = 1
+		for range minimaxSolver.StartSearch(sp) {
+		}
+		Expect(utils.PawnValue).
+			To(BeEquivalentTo(120))
+	})
+
+	It("Evaluates with the evaluation parameters set", func() {
+		defer utils.ResetParams()
+
+		minimaxSolver.SetPosition("4k3/8/8/8/8/8/4P3/4K3 w - - 0 1")
+		minimaxSolver.SetOption("pawn value", "120")
+		e := minimaxSolver.Eval()
+		Expect(e.Term(utils.MaterialTerm, board.White).MG).
+			To(BeEquivalentTo(120))
+	})
+
+	It("Evaluates with the network file set", func() {
+		dir, err := ioutil.TempDir("", "minimax")
+		Expect(err).
+			ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		defer nn.UseFile("")
+
+		network := nn.NewNetwork(1)
+		network.OutputBias = 0.5
+		path := filepath.Join(dir, "test.nn")
+		f, err := os.Create(path)
+		Expect(err).
+			ToNot(HaveOccurred())
+		Expect(network.Write(f)).
+			To(Succeed())
+		Expect(f.Close()).
+			To(Succeed())
+
+		minimaxSolver.SetOption("evaluator", nn.EvaluatorName)
+		minimaxSolver.SetOption(nn.EvalFileOptionName, path)
+		e := minimaxSolver.Eval()
+		Expect(e.Term(utils.NetworkTerm, board.White).MG).
+			To(Equal(network.Evaluate(board.NewPosition())))
+	})
+
+	It("Searches with the selected evaluator", func() {
+		var evaluator *solver.Option
+		for _, option := range minimaxSolver.GetOptions() {
+			if option.Name == solver.EvaluatorOptionName {
+				evaluator = option
+			}
+		}
+		Expect(evaluator).
+			ToNot(BeNil())
+		Expect(evaluator.Vars).
+			To(ContainElement(solver.MaterialEvaluatorName))
+
+		minimaxSolver.SetOption("evaluator", solver.MaterialEvaluatorName)
+		minimaxSolver.SetPosition("rnbqkbnr/ppppppp1/7p/6P1/8/8/PPPPPP1P/RNBQKBNR b KQkq - 0 2")
+		sp := solver.NewSearchParams()
+		sp.Depth = 2
+
+		var result []string
+		for result = range minimaxSolver.StartSearch(sp) {
+		}
+		Expect(result[0]).
+			To(Equal("h6g5"))
+	})
+
+	It("Searches with the network evaluator", func() {
+		minimaxSolver.SetOption("evaluator", nn.EvaluatorName)
+		minimaxSolver.SetPosition("rnbqkbnr/ppppppp1/7p/6P1/8/8/PPPPPP1P/RNBQKBNR b KQkq - 0 2")
+		sp := solver.NewSearchParams()
+		sp.Depth = 3
+
+		var result []string
+		for result = range minimaxSolver.StartSearch(sp) {
+		}
+		Expect(result[0]).
+			To(Equal("h6g5"))
+	})
+
+	It("Stops once go mate finds the mate", func() {
+		minimaxSolver.SetPosition("6k1/5ppp/8/8/8/8/5PPP/R5K1 w - - 0 1")
+		sp := solver.NewSearchParams()
+		sp.Mate = 2
+
+		var results [][]string
+		for result := range minimaxSolver.StartSearch(sp) {
+			results = append(results, result)
+		}
+		Expect(results).
+			To(HaveLen(1))
+		Expect(results[0][0]).
+			To(Equal("a1a8"))
+	})
+
+	It("Finds a mate behind a quiet move that pruning would reduce", func() {
+		minimaxSolver.SetPosition("kbK5/pp6/1P6/8/8/8/8/R7 w - - 0 1")
+		sp := solver.NewSearchParams()
+		sp.Mate = 2
+
+		var result []string
+		for result = range minimaxSolver.StartSearch(sp) {
+		}
+		Expect(result[0]).
+			To(Equal("a1a6"))
+	})
 })
 
 var _ = Describe("MinimaxAlgo", func() {
@@ -53,11 +320,18 @@ var _ = Describe("MinimaxAlgo", func() {
 		return true
 	}
 
+	position := func(game *chess.Game) *board.Position {
+		pos, err := board.ParseFEN(game.Position().String())
+		Expect(err).
+			ToNot(HaveOccurred())
+		return pos
+	}
+
 	It("Calls submit", func() {
 		game := chess.NewGame(chess.UseNotation(chess.LongAlgebraicNotation{}))
 
 		algo := newMinimaxAlgo(1, 32, submit, emitter)
-		algo.Start(game.Position())
+		algo.Start(position(game))
 
 		Expect(called).
 			To(BeTrue())
@@ -73,7 +347,7 @@ var _ = Describe("MinimaxAlgo", func() {
 		}
 
 		algo := newMinimaxAlgo(1, 32, submit, emitter)
-		algo.Start(game.Position())
+		algo.Start(position(game))
 
 		actual := len(submitted)
 		Expect(actual > expected).
@@ -84,12 +358,148 @@ var _ = Describe("MinimaxAlgo", func() {
 		}
 	})
 
+	It("Submits the best move of each completed iteration", func() {
+		game := chess.NewGame(chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(3, 32, submit, emitter)
+		algo.Start(position(game))
+		Expect(submitted).
+			To(HaveLen(3))
+	})
+
+	It("Discards the iteration in progress when stopped", func() {
+		game := chess.NewGame(chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(MaxPly, 32, submit, emitter)
+		algo.Stop()
+		algo.Start(position(game))
+		Expect(called).
+			To(BeFalse())
+	})
+
+	It("Doesn't hang the queen at the horizon", func() {
+		fen, _ := chess.FEN("4k3/8/4p3/3p4/8/8/8/3QK3 w - - 0 1")
+		game := chess.NewGame(fen, chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(1, 32, submit, emitter)
+		algo.Start(position(game))
+		best := submitted[len(submitted)-1]
+		Expect(best[0]).ToNot(Equal("d1d5"))
+	})
+
+	It("Reports seldepth beyond the nominal depth", func() {
+		fakeEmitter := &hf.FakeEmitter{}
+		fen, _ := chess.FEN("4k3/8/4p3/3p4/8/8/8/3QK3 w - - 0 1")
+		game := chess.NewGame(fen, chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(1, 32, submit, fakeEmitter)
+		algo.CheckExtensions = false
+		algo.Start(position(game))
+		i := fakeEmitter.EmitInfoArgsForCall(fakeEmitter.EmitInfoCallCount() - 1)
+		Expect(i.String()).
+			To(ContainSubstring("depth 1 seldepth 4"))
+	})
+
+	It("Searches quiet check evasions in quiescence", func() {
+		// up a queen for a rook, but the only evasion, Qb1, loses to Rxb1#
+		pos, err := board.ParseFEN("7k/1Q6/8/8/8/8/6PP/r6K w - - 0 1")
+		Expect(err).
+			ToNot(HaveOccurred())
+		algo := newMinimaxAlgo(1, 32, submit, emitter)
+		algo.prepare(pos)
+
+		score := algo.quiesce(pos, 0, -math.MaxInt64, math.MaxInt64)
+		Expect(score).
+			To(Equal(utils.MatedIn(2)))
+	})
+
+	It("Submits a move to ponder on", func() {
+		game := chess.NewGame(chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(3, 32, submit, emitter)
+		algo.Start(position(game))
+		best := submitted[len(submitted)-1]
+		Expect(best).
+			To(HaveLen(2))
+
+		Expect(game.MoveStr(best[0])).
+			To(Succeed())
+		Expect(game.MoveStr(best[1])).
+			To(Succeed())
+	})
+
+	It("Reports the principal variation of each iteration", func() {
+		fakeEmitter := &hf.FakeEmitter{}
+		game := chess.NewGame(chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(3, 32, submit, fakeEmitter)
+		algo.Start(position(game))
+
+		Expect(fakeEmitter.EmitInfoCallCount()).
+			To(Equal(3))
+		i := fakeEmitter.EmitInfoArgsForCall(2)
+		Expect(i.String()).
+			To(MatchRegexp(`^info depth 3 seldepth \d+ time \d+ nodes \d+ hashfull \d+ nps \d+ score cp -?\d+ pv( \w+){3,}$`))
+	})
+
+	It("Prefers the shortest mate and reports it in moves", func() {
+		fakeEmitter := &hf.FakeEmitter{}
+		fen, _ := chess.FEN("6k1/5ppp/8/8/8/8/5PPP/R5K1 w - - 0 1")
+		game := chess.NewGame(fen, chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(3, 32, submit, fakeEmitter)
+		algo.Start(position(game))
+
+		best := submitted[len(submitted)-1]
+		Expect(best[0]).
+			To(Equal("a1a8"))
+		i := fakeEmitter.EmitInfoArgsForCall(fakeEmitter.EmitInfoCallCount() - 1)
+		Expect(i.String()).
+			To(MatchRegexp(`score mate 1 pv( \w+)+$`))
+	})
+
+	It("Reports being mated with negative distance", func() {
+		fakeEmitter := &hf.FakeEmitter{}
+		fen, _ := chess.FEN("7k/8/6K1/8/8/8/8/R7 b - - 0 1")
+		game := chess.NewGame(fen, chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(2, 32, submit, fakeEmitter)
+		algo.Start(position(game))
+
+		i := fakeEmitter.EmitInfoArgsForCall(fakeEmitter.EmitInfoCallCount() - 1)
+		Expect(i.String()).
+			To(MatchRegexp(`score mate -1 pv( \w+)+$`))
+	})
+
+	It("Completes the first iteration despite the node limit", func() {
+		game := chess.NewGame(chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(MaxPly, 32, submit, emitter)
+		algo.MaxNodes = 1
+		algo.Start(position(game))
+		Expect(submitted).
+			To(HaveLen(1))
+	})
+
+	It("Avoids a draw by the fifty-move rule when ahead", func() {
+		fen, _ := chess.FEN("7k/8/8/8/8/8/P7/K5Q1 w - - 99 80")
+		game := chess.NewGame(fen, chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(2, 32, submit, emitter)
+		algo.Start(position(game))
+
+		best := submitted[len(submitted)-1]
+		Expect(best[0]).
+			To(HavePrefix("a2"))
+	})
+
+	It("Scores draws by contempt", func() {
+		game := chess.NewGame(chess.UseNotation(chess.LongAlgebraicNotation{}))
+		algo := newMinimaxAlgo(1, 32, submit, emitter)
+		algo.Contempt = 25
+		Expect(algo.drawScore()).
+			To(BeEquivalentTo(-25))
+		algo.Start(position(game))
+		Expect(called).
+			To(BeTrue())
+	})
+
 	It("Takes Pawn", func() {
 		fen, _ := chess.FEN("rnbqkbnr/ppppppp1/7p/6P1/8/8/PPPPPP1P/RNBQKBNR b KQkq - 0 2")
 		game := chess.NewGame(fen, chess.UseNotation(chess.LongAlgebraicNotation{}))
 
 		algo := newMinimaxAlgo(3, 128, submit, emitter)
-		algo.Start(game.Position())
+		algo.Start(position(game))
 
 		best := submitted[len(submitted)-1]
 		Expect(best[0]).To(Equal("h6g5"))
@@ -99,8 +509,7 @@ var _ = Describe("MinimaxAlgo", func() {
 
 func BenchmarkTakePawnSelected(b *testing.B) {
 	emitter := &hf.FakeEmitter{}
-	fen, _ := chess.FEN("rnbqkbnr/ppppppp1/7p/6P1/8/8/PPPPPP1P/RNBQKBNR b KQkq - 0 2")
-	game := chess.NewGame(fen, chess.UseNotation(chess.LongAlgebraicNotation{}))
+	position, _ := board.ParseFEN("rnbqkbnr/ppppppp1/7p/6P1/8/8/PPPPPP1P/RNBQKBNR b KQkq - 0 2")
 
 	submit := func(move []string) bool {
 		return true
@@ -108,14 +517,13 @@ func BenchmarkTakePawnSelected(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		minimax := newMinimaxAlgo(3, 32, submit, emitter)
-		minimax.Start(game.Position())
+		minimax.Start(position)
 	}
 }
 
 func Benchmark2(b *testing.B) {
 	emitter := &hf.FakeEmitter{}
-	fen, _ := chess.FEN("rnb1k2r/pppp1ppp/5n2/8/P7/R1PP4/1P1K2Pq/1NBQ1BR1 w kq - 0 11")
-	game := chess.NewGame(fen, chess.UseNotation(chess.LongAlgebraicNotation{}))
+	position, _ := board.ParseFEN("rnb1k2r/pppp1ppp/5n2/8/P7/R1PP4/1P1K2Pq/1NBQ1BR1 w kq - 0 11")
 
 	submit := func(move []string) bool {
 		return true
@@ -123,6 +531,6 @@ func Benchmark2(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		minimax := newMinimaxAlgo(3, 32, submit, emitter)
-		minimax.Start(game.Position())
+		minimax.Start(position)
 	}
 }
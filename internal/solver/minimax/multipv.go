@@ -0,0 +1,80 @@
+package minimax
+
+import (
+	"math"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+// rootLine is a principal variation of the root, found by one iteration.
+type rootLine struct {
+	move  board.Move
+	score utils.CentiPawns
+	pv    []string
+}
+
+// searchLines searches position to depth for MultiPV principal variations, or
+// as many as there are root moves, best first.  Each line is searched with the
+// root moves of the lines before it excluded, with an aspiration window around
+// the score of the same line in previous, the lines of the last iteration, and
+// reported once found.  A line that fails low is reported with the move of the
+// same line in previous, unless an earlier line found it.  The lines completed
+// before the search stopped are returned.
+func (minimax *minimaxAlgo) searchLines(position *board.Position, depth int,
+	previous []rootLine, moves ...board.Move) []rootLine {
+
+	n := minimax.MultiPV
+	if n > 1 {
+		if len(moves) == 0 {
+			moves = position.LegalMoves(nil)
+		}
+		if n > len(moves) {
+			n = len(moves)
+		}
+	}
+
+	lines := make([]rootLine, 0, n)
+	for minimax.line = 0; minimax.line < n; minimax.line++ {
+		last := rootLine{move: board.NoMove}
+		if minimax.line < len(previous) {
+			last = previous[minimax.line]
+		}
+		if len(excludeLines([]board.Move{last.move}, lines)) == 0 {
+			// found by an earlier line this time
+			last.move = board.NoMove
+		}
+
+		score := minimax.aspiration(position, depth, last.score, last.move, excludeLines(moves, lines)...)
+		if minimax.Stopped() || minimax.rootMove == board.NoMove {
+			break
+		}
+
+		line := rootLine{minimax.rootMove, score, minimax.pv.Line()}
+		lines = append(lines, line)
+		minimax.executeBestMoveCallbacks(line.move, depth, score, -math.MaxInt64, math.MaxInt64)
+	}
+	return lines
+}
+
+// excludeLines returns the moves that aren't the first move of any of lines.
+func excludeLines(moves []board.Move, lines []rootLine) []board.Move {
+	if len(lines) == 0 {
+		return moves
+	}
+
+	remaining := make([]board.Move, 0, len(moves))
+	for _, move := range moves {
+		excluded := false
+		for _, line := range lines {
+			if line.move == move {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			remaining = append(remaining, move)
+		}
+	}
+	return remaining
+}
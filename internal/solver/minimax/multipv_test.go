@@ -0,0 +1,130 @@
+package minimax
+
+import (
+	"regexp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	hf "github.com/mhv2109/uci-impl/internal/handler/handlerfakes"
+	"github.com/mhv2109/uci-impl/internal/solver"
+)
+
+// pvMove matches the first move of the principal variation of an info line.
+var pvMove = regexp.MustCompile(` pv (\w+)`)
+
+var _ = Describe("MultiPV", func() {
+	var (
+		algo      *minimaxAlgo
+		emitter   *hf.FakeEmitter
+		submitted [][]string
+	)
+
+	BeforeEach(func() {
+		submitted = nil
+		emitter = &hf.FakeEmitter{}
+		algo = newTestAlgo(3, emitter, &submitted)
+	})
+
+	// reported returns the info lines reported so far.
+	reported := func() []string {
+		var lines []string
+		for j := 0; j < emitter.EmitInfoCallCount(); j++ {
+			i := emitter.EmitInfoArgsForCall(j)
+			lines = append(lines, i.String())
+		}
+		return lines
+	}
+
+	It("Reports each line of every iteration with a different move", func() {
+		algo.MultiPV = 3
+		algo.Start(parse(board.StartFEN))
+
+		lines := reported()
+		Expect(lines).
+			To(HaveLen(9))
+		first := map[string]bool{}
+		for k, line := range lines[6:] {
+			Expect(line).
+				To(MatchRegexp(`^info depth 3 seldepth \d+ multipv %d .* score cp -?\d+ pv( \w+)+$`, k+1))
+
+			first[pvMove.FindStringSubmatch(line)[1]] = true
+		}
+		Expect(first).
+			To(HaveLen(3))
+	})
+
+	It("Submits the move of the best line", func() {
+		algo.MultiPV = 3
+		algo.Start(parse("6k1/5ppp/8/8/8/8/5PPP/R5K1 w - - 0 1"))
+
+		Expect(submitted[len(submitted)-1][0]).
+			To(Equal("a1a8"))
+		lines := reported()
+		Expect(lines[len(lines)-3]).
+			To(MatchRegexp(`multipv 1 .* score mate 1 pv a1a8$`))
+		Expect(lines[len(lines)-2]).
+			ToNot(ContainSubstring("score mate 1"))
+	})
+
+	It("Keeps the best line's move for the root in the transposition table", func() {
+		algo.MultiPV = 3
+		position := parse("6k1/5ppp/8/8/8/8/5PPP/R5K1 w - - 0 1")
+		best, err := position.ParseMove("a1a8")
+		Expect(err).
+			ToNot(HaveOccurred())
+		algo.Start(position)
+
+		entry, ok := algo.tt.Probe(position.Key())
+		Expect(ok).
+			To(BeTrue())
+		Expect(entry.move.Matches(best)).
+			To(BeTrue())
+	})
+
+	It("Reports no more lines than there are root moves", func() {
+		algo.MultiPV = 5
+		algo.MaxDepth = 2
+		position := parse("7k/8/8/8/8/8/8/K7 w - - 0 1")
+		algo.Start(position)
+		Expect(emitter.EmitInfoCallCount()).
+			To(Equal(6))
+
+		emitter = &hf.FakeEmitter{}
+		algo.emitter = emitter
+		algo.Start(position, position.LegalMoves(nil)[:2]...)
+		Expect(emitter.EmitInfoCallCount()).
+			To(Equal(4))
+	})
+
+	It("Is set by the MultiPV option", func() {
+		minimaxSolver := NewMinimaxSolverWithEmitter(emitter)
+		minimaxSolver.SetOption("MultiPV", "2")
+		minimaxSolver.SetPosition(board.StartFEN)
+		sp := solver.NewSearchParams()
+		sp.Depth = 1
+
+		for range minimaxSolver.StartSearch(sp) {
+		}
+		Expect(reported()).
+			To(HaveLen(2))
+		Expect(reported()[1]).
+			To(ContainSubstring("multipv 2"))
+	})
+
+	It("Excludes the moves of lines already found", func() {
+		moves := parse(board.StartFEN).LegalMoves(nil)
+		lines := []rootLine{{move: moves[3]}, {move: moves[0]}}
+
+		remaining := excludeLines(moves, lines)
+		Expect(remaining).
+			To(HaveLen(len(moves) - 2))
+		Expect(remaining).
+			ToNot(ContainElement(moves[0]))
+		Expect(remaining).
+			ToNot(ContainElement(moves[3]))
+		Expect(excludeLines(moves, nil)).
+			To(Equal(moves))
+	})
+})
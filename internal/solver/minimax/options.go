@@ -1,11 +1,15 @@
 package minimax
 
 import (
+	"strconv"
+
 	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/nn"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
 )
 
 func availableOptions() []*solver.Option {
-	options := make([]*solver.Option, 3, 3)
+	options := make([]*solver.Option, 15, 15)
 
 	UCI_EngineAboutOption := &solver.Option{
 		Name:    "UCI_EngineAboutOption",
@@ -22,13 +26,89 @@ func availableOptions() []*solver.Option {
 	DepthOption := &solver.Option{
 		Name:    "Search Depth",
 		Type:    solver.OptionSpinType,
-		Default: "2",
+		Default: "4",
 		Min:     "1",
-		Max:     "4"}
+		Max:     strconv.Itoa(MaxPly)}
+
+	RandomMoveOrderOption := &solver.Option{
+		Name:    "Random Move Order",
+		Type:    solver.OptionCheckType,
+		Default: "false"}
+
+	ContemptOption := &solver.Option{
+		Name:    "Contempt",
+		Type:    solver.OptionSpinType,
+		Default: "0",
+		Min:     "-100",
+		Max:     "100"}
+
+	ThreadsOption := &solver.Option{
+		Name:    "Threads",
+		Type:    solver.OptionSpinType,
+		Default: "1",
+		Min:     "1",
+		Max:     "256"}
+
+	MultiPVOption := &solver.Option{
+		Name:    "MultiPV",
+		Type:    solver.OptionSpinType,
+		Default: "1",
+		Min:     "1",
+		Max:     "500"}
+
+	UCI_ShowCurrLineOption := &solver.Option{
+		Name:    "UCI_ShowCurrLine",
+		Type:    solver.OptionCheckType,
+		Default: "false"}
+
+	// search techniques, which can be turned off for testing
+	PVSOption := &solver.Option{
+		Name:    "Principal Variation Search",
+		Type:    solver.OptionCheckType,
+		Default: "true"}
+
+	NullMoveOption := &solver.Option{
+		Name:    "Null Move Pruning",
+		Type:    solver.OptionCheckType,
+		Default: "true"}
+
+	LMROption := &solver.Option{
+		Name:    "Late Move Reductions",
+		Type:    solver.OptionCheckType,
+		Default: "true"}
+
+	CheckExtensionsOption := &solver.Option{
+		Name:    "Check Extensions",
+		Type:    solver.OptionCheckType,
+		Default: "true"}
 
 	options[0] = UCI_EngineAboutOption
 	options[1] = HashOption
 	options[2] = DepthOption
+	options[3] = solver.NewMoveOverheadOption()
+	options[4] = RandomMoveOrderOption
+	options[5] = ContemptOption
+	options[6] = solver.NewEvaluatorOption()
+	options[7] = nn.NewEvalFileOption()
+	options[8] = ThreadsOption
+	options[9] = MultiPVOption
+	options[10] = UCI_ShowCurrLineOption
+	options[11] = PVSOption
+	options[12] = NullMoveOption
+	options[13] = LMROption
+	options[14] = CheckExtensionsOption
+
+	// evaluation parameters, for tuning; the piece-square tables are only
+	// exposed as a percentage scale of each table ("Knight Squares MG" etc.),
+	// not square by square
+	for _, param := range utils.Params() {
+		options = append(options, &solver.Option{
+			Name:    param.Name,
+			Type:    solver.OptionSpinType,
+			Default: strconv.Itoa(param.Default),
+			Min:     strconv.Itoa(param.Min),
+			Max:     strconv.Itoa(param.Max)})
+	}
 
 	return options
 }
@@ -0,0 +1,160 @@
+package minimax
+
+import (
+	"sort"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+// Move ordering scores, moves are searched in descending order of score.
+const (
+	hashMoveScore = 1 << 30
+	captureScore  = 1 << 24 // plus MVV-LVA score
+	killerScore   = 1 << 22 // first killer, the second killer scores one less
+	maxHistory    = 1 << 20 // history scores of quiet moves are kept below this
+)
+
+// orderingValues are piece values used by MVV-LVA, so that the Most Valuable
+// Victim is captured first, by the Least Valuable Attacker.
+var orderingValues = [...]int{
+	board.Pawn:   1,
+	board.Knight: 2,
+	board.Bishop: 3,
+	board.Rook:   4,
+	board.Queen:  5,
+	board.King:   6,
+}
+
+const nKillers = 2
+
+// moveOrderer orders moves so alpha-beta pruning cuts off as early as
+// possible: the hash move, then captures by MVV-LVA, then killer moves, then
+// the remaining quiet moves by the history heuristic.
+type moveOrderer struct {
+	killers [MaxPly + 1][nKillers]ttMove
+	history [2][nSquares][nSquares]int // indexed by color, origin and destination squares
+	scores  [MaxPly + 1][]int          // move scores, reused at each ply
+}
+
+const nSquares = 64
+
+// maxMoves is more than the number of legal moves in any position.
+const maxMoves = 256
+
+func newMoveOrderer() *moveOrderer {
+	orderer := &moveOrderer{}
+	for ply := range orderer.scores {
+		orderer.scores[ply] = make([]int, maxMoves)
+	}
+	return orderer
+}
+
+// NewSearch forgets the killer moves and ages the history of the previous
+// search.
+func (orderer *moveOrderer) NewSearch() {
+	orderer.killers = [MaxPly + 1][nKillers]ttMove{}
+	for c := range orderer.history {
+		for s1 := range orderer.history[c] {
+			for s2 := range orderer.history[c][s1] {
+				orderer.history[c][s1][s2] /= 2
+			}
+		}
+	}
+}
+
+// Clear forgets everything learnt.
+func (orderer *moveOrderer) Clear() {
+	orderer.killers = [MaxPly + 1][nKillers]ttMove{}
+	orderer.history = [2][nSquares][nSquares]int{}
+}
+
+// Order sorts moves in position at ply, trying hashMove first.  Moves with the
+// same score keep their relative order.
+func (orderer *moveOrderer) Order(position *board.Position, ply int,
+	hashMove ttMove, moves []board.Move) []board.Move {
+
+	scores := orderer.scores[ply][:len(moves)]
+	for i, move := range moves {
+		scores[i] = orderer.score(position, ply, hashMove, move)
+	}
+	sort.Stable(scoredMoves{moves, scores})
+	return moves
+}
+
+func (orderer *moveOrderer) score(position *board.Position, ply int,
+	hashMove ttMove, move board.Move) int {
+
+	if hashMove.Matches(move) {
+		return hashMoveScore
+	}
+
+	if victim := capturedPiece(position, move); victim != board.NoPieceType {
+		attacker := position.Piece(move.From()).Type()
+		return captureScore + orderingValues[victim]*8 - orderingValues[attacker] + promotionScore(move)
+	} else if promo := promotionScore(move); promo > 0 {
+		return captureScore + promo
+	}
+
+	m := newTTMove(move)
+	for i, killer := range orderer.killers[ply] {
+		if killer == m {
+			return killerScore - i
+		}
+	}
+
+	return orderer.history[position.Turn()][move.From()][move.To()]
+}
+
+// Update records a quiet move that caused a beta cutoff at ply with depth
+// remaining, so it is tried early in sibling nodes and other positions.
+func (orderer *moveOrderer) Update(position *board.Position, ply, depth int, move board.Move) {
+	if move.IsNoisy() {
+		return
+	}
+
+	if m := newTTMove(move); orderer.killers[ply][0] != m {
+		copy(orderer.killers[ply][1:], orderer.killers[ply][:nKillers-1])
+		orderer.killers[ply][0] = m
+	}
+
+	history := &orderer.history[position.Turn()][move.From()][move.To()]
+	if *history += depth * depth; *history >= maxHistory {
+		*history = maxHistory - 1
+	}
+}
+
+// capturedPiece returns the type of the piece move captures, or
+// board.NoPieceType.
+func capturedPiece(position *board.Position, move board.Move) board.PieceType {
+	if move.IsEnPassant() {
+		return board.Pawn
+	}
+	return position.Piece(move.To()).Type()
+}
+
+// promotionScore favors promotions to a queen, underpromotions are rarely
+// better.
+func promotionScore(move board.Move) int {
+	if move.Promo() == board.Queen {
+		return orderingValues[board.Queen] * 8
+	}
+	return 0
+}
+
+type scoredMoves struct {
+	moves  []board.Move
+	scores []int
+}
+
+func (s scoredMoves) Len() int {
+	return len(s.moves)
+}
+
+func (s scoredMoves) Less(i, j int) bool {
+	return s.scores[i] > s.scores[j]
+}
+
+func (s scoredMoves) Swap(i, j int) {
+	s.moves[i], s.moves[j] = s.moves[j], s.moves[i]
+	s.scores[i], s.scores[j] = s.scores[j], s.scores[i]
+}
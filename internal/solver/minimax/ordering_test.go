@@ -0,0 +1,77 @@
+package minimax
+
+import (
+	"github.com/mhv2109/uci-impl/internal/board"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MoveOrderer", func() {
+	var (
+		orderer  *moveOrderer
+		position *board.Position
+	)
+
+	// white can capture the queen on d5 with the pawn or the queen, or the
+	// pawn on a7 with the rook
+	const fen = "4k3/p7/8/3q4/4P3/8/8/R2QK3 w - - 0 1"
+
+	find := func(s string) board.Move {
+		move, err := position.ParseMove(s)
+		Expect(err).
+			ToNot(HaveOccurred())
+		return move
+	}
+
+	order := func(hashMove ttMove) []string {
+		moves := orderer.Order(position, 1, hashMove, position.LegalMoves(nil))
+		ret := make([]string, len(moves))
+		for i, move := range moves {
+			ret[i] = move.String()
+		}
+		return ret
+	}
+
+	BeforeEach(func() {
+		orderer = newMoveOrderer()
+		position, _ = board.ParseFEN(fen)
+	})
+
+	It("Tries the hash move first", func() {
+		hashMove := newTTMove(find("a1b1"))
+		Expect(order(hashMove)[0]).
+			To(Equal("a1b1"))
+	})
+
+	It("Orders captures by MVV-LVA", func() {
+		Expect(order(noTTMove)[:3]).
+			To(Equal([]string{"e4d5", "d1d5", "a1a7"}))
+	})
+
+	It("Tries killers before other quiet moves", func() {
+		orderer.Update(position, 1, 1, find("e1f2"))
+		Expect(order(noTTMove)[3]).
+			To(Equal("e1f2"))
+	})
+
+	It("Keeps killers per ply", func() {
+		orderer.Update(position, 2, 1, find("e1f2"))
+		Expect(orderer.score(position, 1, noTTMove, find("e1f2"))).
+			To(BeNumerically("<", killerScore-1))
+		Expect(orderer.score(position, 2, noTTMove, find("e1f2"))).
+			To(Equal(killerScore))
+	})
+
+	It("Orders quiet moves by history", func() {
+		orderer.Update(position, 5, 1, find("a1b1"))
+		orderer.Update(position, 5, 3, find("d1d2"))
+		Expect(order(noTTMove)[3:5]).
+			To(Equal([]string{"d1d2", "a1b1"}))
+	})
+
+	It("Doesn't record captures as killers", func() {
+		orderer.Update(position, 1, 1, find("a1a7"))
+		Expect(orderer.killers[1][0]).
+			To(Equal(noTTMove))
+	})
+})
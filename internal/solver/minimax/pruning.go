@@ -0,0 +1,76 @@
+package minimax
+
+import (
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+const (
+	// nullMoveMinDepth is the least depth left at which null move pruning is
+	// tried.
+	nullMoveMinDepth = 3
+	// lmrMinDepth is the least depth left at which moves are reduced.
+	lmrMinDepth = 3
+	// lmrMinMoves is the number of moves of a node searched before any is
+	// reduced, enough for the hash move and the best captures.
+	lmrMinMoves = 3
+)
+
+// nullMoveAllowed returns true if null move pruning may cut off the non-PV
+// node at ply with depth left.  Passing the move is a good guess at a lower
+// bound of the score, as a move nearly always improves the position, unless in
+// zugzwang.  Zugzwang is common when the side to move has only its king and
+// pawns, so the pruning is skipped then, as it is after a null move, which
+// would search the same position again, and when the static score is already
+// below beta.
+func (minimax *minimaxAlgo) nullMoveAllowed(position *board.Position, depth, ply int,
+	beta utils.CentiPawns) bool {
+
+	if !minimax.NullMove || ply == 0 || depth < nullMoveMinDepth || utils.IsMate(beta) ||
+		position.LastMove() == board.NoMove {
+		return false
+	}
+
+	us := position.Turn()
+	pieces := position.Occupied(us) &^ position.Pieces(us, board.Pawn) &^ position.Pieces(us, board.King)
+	if pieces == 0 {
+		return false
+	}
+	return minimax.score(position, ply, true) >= beta
+}
+
+// nullMoveSearch searches position after passing the move with a null window
+// at beta, to a depth reduced by 2 plies, or 3 plies with much depth left.  A
+// score of at least beta means the node would fail high anyway.
+func (minimax *minimaxAlgo) nullMoveSearch(position *board.Position, depth, ply int,
+	beta utils.CentiPawns) utils.CentiPawns {
+
+	r := 2
+	if depth > 6 {
+		r = 3
+	}
+
+	position.MakeNullMove()
+	score := -minimax.search(position, depth-1-r, ply+1, -beta, -beta+1)
+	position.UnmakeNullMove()
+
+	return score
+}
+
+// reduction returns how many plies less to search the move at index i of a
+// node with depth left, where position is after the move.  Moves late in the
+// ordering rarely turn out best, so quiet ones are searched shallower, and
+// searched again at full depth if they beat alpha after all.  Captures,
+// promotions, check evasions and checks are never reduced.
+func (minimax *minimaxAlgo) reduction(position *board.Position, depth, i int, inCheck bool,
+	move board.Move) int {
+
+	if !minimax.LateMoveReductions || depth < lmrMinDepth || i < lmrMinMoves ||
+		inCheck || move.IsNoisy() || position.InCheck() {
+		return 0
+	}
+	if depth >= 6 && i >= 2*lmrMinMoves {
+		return 2
+	}
+	return 1
+}
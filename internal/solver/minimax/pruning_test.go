@@ -0,0 +1,162 @@
+package minimax
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	hf "github.com/mhv2109/uci-impl/internal/handler/handlerfakes"
+)
+
+var _ = Describe("Search techniques", func() {
+	var (
+		algo      *minimaxAlgo
+		submitted [][]string
+	)
+
+	BeforeEach(func() {
+		submitted = nil
+		algo = newTestAlgo(5, &hf.FakeEmitter{}, &submitted)
+	})
+
+	disableAll := func() {
+		algo.PVS = false
+		algo.NullMove = false
+		algo.LateMoveReductions = false
+		algo.CheckExtensions = false
+	}
+
+	// nodes returns the nodes searched from fen with a cleared table.
+	nodes := func(fen string) int {
+		algo.Clear()
+		algo.Start(parse(fen))
+		return algo.totalNodes()
+	}
+
+	It("Are all enabled by default", func() {
+		Expect(algo.PVS && algo.NullMove && algo.LateMoveReductions && algo.CheckExtensions).
+			To(BeTrue())
+	})
+
+	It("Each change the search when turned off", func() {
+		all := nodes(middlegame)
+		for _, technique := range []*bool{&algo.PVS, &algo.NullMove, &algo.LateMoveReductions, &algo.CheckExtensions} {
+			*technique = false
+			Expect(nodes(middlegame)).
+				ToNot(Equal(all))
+			*technique = true
+		}
+
+		disableAll()
+		Expect(nodes(middlegame)).
+			To(BeNumerically(">", all))
+	})
+
+	It("Finds the same score with and without a null window", func() {
+		disableAll()
+		for _, fen := range []string{board.StartFEN, middlegame,
+			"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1"} {
+			position := parse(fen)
+
+			algo.PVS = false
+			algo.Clear()
+			algo.prepare(position)
+			alphaBeta := algo.search(position, 4, 0, -math.MaxInt64, math.MaxInt64)
+
+			algo.PVS = true
+			algo.Clear()
+			algo.prepare(position)
+			Expect(algo.search(position, 4, 0, -math.MaxInt64, math.MaxInt64)).
+				To(Equal(alphaBeta), fen)
+		}
+	})
+
+	It("Still finds mates", func() {
+		algo.Start(parse("6k1/5ppp/8/8/8/8/5PPP/R5K1 w - - 0 1"))
+		Expect(submitted[len(submitted)-1][0]).
+			To(Equal("a1a8"))
+
+		algo.Start(parse("r1bqkb1r/pppp1ppp/2n2n2/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR w KQkq - 4 4"))
+		Expect(submitted[len(submitted)-1][0]).
+			To(Equal("h5f7"))
+	})
+
+	Describe("Null move pruning", func() {
+		// after returns the position after lan is played in fen, as a node of
+		// the search
+		after := func(fen, lan string) *board.Position {
+			position := parse(fen)
+			algo.prepare(position)
+			move, err := position.ParseMove(lan)
+			Expect(err).
+				ToNot(HaveOccurred())
+			position.MakeMove(move)
+			return position
+		}
+
+		It("Is tried after a move when the static score is at least beta", func() {
+			position := after("4k3/4p3/8/8/8/8/4P3/3NK3 b - - 0 1", "e8d8")
+			Expect(algo.nullMoveAllowed(position, 4, 1, -1000)).
+				To(BeTrue())
+			Expect(algo.nullMoveAllowed(position, 4, 1, 1000)).
+				To(BeFalse())
+			Expect(algo.nullMoveAllowed(position, 2, 1, -1000)).
+				To(BeFalse())
+
+			algo.NullMove = false
+			Expect(algo.nullMoveAllowed(position, 4, 1, -1000)).
+				To(BeFalse())
+		})
+
+		It("Is skipped in pawn endings, where zugzwang is common", func() {
+			position := after("4k3/4p3/8/8/8/8/4P3/4K3 b - - 0 1", "e8d8")
+			Expect(algo.nullMoveAllowed(position, 4, 1, -1000)).
+				To(BeFalse())
+		})
+
+		It("Is skipped right after a null move", func() {
+			position := after("3nk3/4p3/8/8/8/8/4P3/3NK3 w - - 0 1", "e1f1")
+			position.MakeNullMove()
+			Expect(algo.nullMoveAllowed(position, 4, 2, -1000)).
+				To(BeFalse())
+		})
+	})
+
+	Describe("Late move reductions", func() {
+		reduction := func(fen, lan string, depth, i int) int {
+			position := parse(fen)
+			inCheck := position.InCheck()
+			move, err := position.ParseMove(lan)
+			Expect(err).
+				ToNot(HaveOccurred())
+			position.MakeMove(move)
+			return algo.reduction(position, depth, i, inCheck, move)
+		}
+
+		It("Reduces late quiet moves", func() {
+			Expect(reduction(middlegame, "a2a3", 4, 10)).
+				To(Equal(1))
+			Expect(reduction(middlegame, "a2a3", 8, 10)).
+				To(Equal(2))
+		})
+
+		It("Doesn't reduce early moves, shallow nodes, captures or checks", func() {
+			Expect(reduction(middlegame, "a2a3", 4, 1)).
+				To(BeZero())
+			Expect(reduction(middlegame, "a2a3", 2, 10)).
+				To(BeZero())
+			Expect(reduction(middlegame, "c4d5", 4, 10)).
+				To(BeZero())
+			Expect(reduction("4k3/8/8/8/8/8/8/R3K3 w - - 0 1", "a1a8", 4, 10)).
+				To(BeZero())
+		})
+
+		It("Can be turned off", func() {
+			algo.LateMoveReductions = false
+			Expect(reduction(middlegame, "a2a3", 4, 10)).
+				To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,34 @@
+package minimax
+
+import (
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+// pvTable is a triangular table of principal variations, where row ply holds
+// the best line found so far from the node at ply.  A node clears its row on
+// entry, and prepends its best move to its child's row when it finds one.
+type pvTable struct {
+	moves  [MaxPly + 1][MaxPly + 1]board.Move
+	length [MaxPly + 1]int
+}
+
+// Clear empties the line at ply.
+func (pv *pvTable) Clear(ply int) {
+	pv.length[ply] = ply
+}
+
+// Update sets the line at ply to move followed by the line at ply+1.
+func (pv *pvTable) Update(ply int, move board.Move) {
+	pv.moves[ply][ply] = move
+	copy(pv.moves[ply][ply+1:], pv.moves[ply+1][ply+1:pv.length[ply+1]])
+	pv.length[ply] = pv.length[ply+1]
+}
+
+// Line returns the principal variation from the root.
+func (pv *pvTable) Line() []string {
+	line := make([]string, 0, pv.length[0])
+	for _, move := range pv.moves[0][:pv.length[0]] {
+		line = append(line, move.String())
+	}
+	return line
+}
@@ -0,0 +1,81 @@
+package minimax
+
+import (
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+// quiesce extends the search past the nominal depth, searching only captures
+// and promotions until the position is quiet.  The static score of the
+// position is used as a lower bound ("stand pat"), as the side to move is
+// rarely forced to make a capture.  In check, the side to move may have to
+// make a bad move, so all evasions are searched instead, and a position
+// without any is scored as mate.
+func (minimax *minimaxAlgo) quiesce(position *board.Position, ply int,
+	alpha, beta utils.CentiPawns) utils.CentiPawns {
+
+	minimax.pv.Clear(ply)
+	minimax.visit(position, ply)
+
+	inCheck := position.InCheck()
+	moves, hasMoves := minimax.getQuiescenceMoves(position, ply, inCheck)
+	if !hasMoves {
+		return minimax.score(position, ply, false)
+	} else if minimax.Stopped() || ply >= MaxPly {
+		return minimax.score(position, ply, true)
+	}
+
+	if !inCheck {
+		standPat := minimax.score(position, ply, true)
+		if standPat >= beta {
+			return beta
+		}
+		if standPat > alpha {
+			alpha = standPat
+		}
+	}
+
+	for _, move := range moves {
+		minimax.play(position, move)
+		score := -minimax.quiesce(position, ply+1, -beta, -alpha)
+		minimax.undo(position)
+
+		if score > alpha {
+			alpha = score
+			minimax.pv.Update(ply, move)
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	return alpha
+}
+
+func (minimax *minimaxAlgo) updateSeldepth(ply int) {
+	if ply > minimax.seldepth {
+		minimax.seldepth = ply
+	}
+}
+
+// getQuiescenceMoves returns the captures and promotions available in
+// position, or all moves if evasions is true, in the order to search them, and
+// whether there are any legal moves at all.
+func (minimax *minimaxAlgo) getQuiescenceMoves(position *board.Position, ply int,
+	evasions bool) ([]board.Move, bool) {
+
+	moves := position.LegalMoves(minimax.moves[ply][:0])
+	minimax.moves[ply] = moves
+	hasMoves := len(moves) > 0
+	if evasions {
+		return minimax.orderer.Order(position, ply, noTTMove, moves), hasMoves
+	}
+
+	noisy := moves[:0]
+	for _, move := range moves {
+		if move.IsNoisy() {
+			noisy = append(noisy, move)
+		}
+	}
+	return minimax.orderer.Order(position, ply, noTTMove, noisy), hasMoves
+}
@@ -0,0 +1,99 @@
+package minimax
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+// Searches with more than one thread use Lazy SMP: helper threads search the
+// same position as the main thread, sharing only the transposition table.  The
+// results they store let the main thread cut off or order moves at nodes it
+// hasn't searched yet.  Only the main thread reports and submits results, and
+// it stops the helpers once its search ends.
+
+// SetThreads sets the number of threads to search with, including the main
+// thread.
+func (minimax *minimaxAlgo) SetThreads(threads int) {
+	if threads < 1 {
+		threads = 1
+	}
+	if len(minimax.helpers) > threads-1 {
+		minimax.helpers = minimax.helpers[:threads-1]
+	}
+	for len(minimax.helpers) < threads-1 {
+		helper := newSearchThread(MaxPly, minimax.HashSize, minimax.tt, nil, minimax.emitter)
+		helper.id = len(minimax.helpers) + 1
+		helper.main = minimax
+		helper.SetEvaluator(minimax.evaluatorName, false)
+		minimax.helpers = append(minimax.helpers, helper)
+	}
+}
+
+// Threads returns the number of threads searching.
+func (minimax *minimaxAlgo) Threads() int {
+	return len(minimax.helpers) + 1
+}
+
+// startHelpers starts the helper threads searching a copy of position each,
+// unless there are no moves to search.  The returned WaitGroup is done once
+// they've stopped.
+func (minimax *minimaxAlgo) startHelpers(position *board.Position, hasMoves bool,
+	moves ...board.Move) *sync.WaitGroup {
+
+	var done sync.WaitGroup
+	if !hasMoves {
+		return &done
+	}
+
+	for _, helper := range minimax.helpers {
+		helper.Randomize = minimax.Randomize
+		helper.Contempt = minimax.Contempt
+		helper.PVS = minimax.PVS
+		helper.NullMove = minimax.NullMove
+		helper.LateMoveReductions = minimax.LateMoveReductions
+		helper.CheckExtensions = minimax.CheckExtensions
+		helper.ShowCurrLine = minimax.ShowCurrLine
+		helper.Reset()
+
+		position := position.Copy()
+		helper.prepare(position)
+
+		done.Add(1)
+		go func(helper *minimaxAlgo) {
+			defer done.Done()
+			helper.help(position, moves...)
+		}(helper)
+	}
+	return &done
+}
+
+// stopHelpers stops the helper threads, and waits until they have.
+func (minimax *minimaxAlgo) stopHelpers(done *sync.WaitGroup) {
+	for _, helper := range minimax.helpers {
+		helper.Stop()
+	}
+	done.Wait()
+}
+
+// help searches position with iterative deepening until stopped.  Odd
+// numbered helpers start a ply deeper, so the threads don't all search the
+// same depth at the same time.
+func (minimax *minimaxAlgo) help(position *board.Position, moves ...board.Move) {
+	for depth := 1 + minimax.id%2; depth <= MaxPly && !minimax.Stopped(); depth++ {
+		minimax.seldepth = 0
+		minimax.search(position, depth, 0, -math.MaxInt64, math.MaxInt64, moves...)
+	}
+}
+
+// totalNodes returns the nodes searched by all threads since the search
+// started.
+func (minimax *minimaxAlgo) totalNodes() int {
+	nodes := atomic.LoadInt64(&minimax.nodes)
+	for _, helper := range minimax.helpers {
+		nodes += atomic.LoadInt64(&helper.nodes)
+	}
+	return int(nodes)
+}
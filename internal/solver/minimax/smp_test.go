@@ -0,0 +1,82 @@
+package minimax
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	hf "github.com/mhv2109/uci-impl/internal/handler/handlerfakes"
+	"github.com/mhv2109/uci-impl/internal/solver"
+)
+
+var _ = Describe("Lazy SMP", func() {
+	var emitter *hf.FakeEmitter
+
+	BeforeEach(func() {
+		emitter = &hf.FakeEmitter{}
+	})
+
+	It("Finds the best move with several threads", func() {
+		minimaxSolver := NewMinimaxSolverWithEmitter(emitter)
+		minimaxSolver.SetOption("Threads", "4")
+		minimaxSolver.SetPosition("6k1/5ppp/8/8/8/8/5PPP/R5K1 w - - 0 1")
+		sp := solver.NewSearchParams()
+		sp.Depth = 4
+
+		var result []string
+		for result = range minimaxSolver.StartSearch(sp) {
+		}
+		Expect(result[0]).
+			To(Equal("a1a8"))
+	})
+
+	It("Counts the nodes of all threads", func() {
+		algo := newMinimaxAlgo(MaxPly, 1, func([]string) bool { return true }, emitter)
+		algo.SetThreads(3)
+		Expect(algo.Threads()).
+			To(Equal(3))
+
+		algo.MaxNodes = 50000
+		algo.Start(board.NewPosition())
+		Expect(algo.totalNodes()).
+			To(BeNumerically(">", int(algo.nodes)))
+
+		algo.SetThreads(1)
+		Expect(algo.totalNodes()).
+			To(BeEquivalentTo(algo.nodes))
+	})
+
+	It("Shares the transposition table", func() {
+		algo := newMinimaxAlgo(1, 1, nil, emitter)
+		algo.SetThreads(2)
+		Expect(algo.helpers[0].tt).
+			To(BeIdenticalTo(algo.tt))
+	})
+
+	It("Reports the current line of each thread", func() {
+		defer func(interval time.Duration) {
+			currLineInterval = interval
+		}(currLineInterval)
+		currLineInterval = 0
+
+		algo := newMinimaxAlgo(MaxPly, 1, func([]string) bool { return true }, emitter)
+		algo.SetThreads(2)
+		algo.ShowCurrLine = true
+		algo.MaxNodes = 200000
+		algo.Start(board.NewPosition())
+
+		cpus := map[string]bool{}
+		for i := 0; i < emitter.EmitInfoCallCount(); i++ {
+			line := emitter.EmitInfoArgsForCall(i)
+			s := line.String()
+			if strings.HasPrefix(s, "info currline ") {
+				cpus[strings.Fields(s)[2]] = true
+			}
+		}
+		Expect(cpus).
+			To(Equal(map[string]bool{"1": true, "2": true}))
+	})
+})
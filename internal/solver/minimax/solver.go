@@ -3,11 +3,14 @@ package minimax
 import (
 	"log"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/mhv2109/uci-impl/internal/board"
 	"github.com/mhv2109/uci-impl/internal/handler"
 	"github.com/mhv2109/uci-impl/internal/solver"
-	"github.com/notnil/chess"
+	"github.com/mhv2109/uci-impl/internal/solver/nn"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
 )
 
 type MinimaxSolver struct {
@@ -15,6 +18,17 @@ type MinimaxSolver struct {
 
 	emitter handler.Emitter
 	algo    *minimaxAlgo
+	reload  bool // the network changed since the evaluator of algo was created
+
+	searching sync.WaitGroup // tracks the running search goroutine
+
+	mutex       sync.Mutex // guards the state of the current search below
+	timeManager *solver.TimeManager
+	timer       *time.Timer // stops a timed search
+	pondering   bool
+	infinite    bool
+	bounded     bool // ends by itself at a "go depth", "go nodes" or "go mate" limit
+	finished    bool
 }
 
 func NewMinimaxSolver() solver.Solver {
@@ -46,7 +60,96 @@ func (solver *MinimaxSolver) getHashSize() int {
 }
 
 func (solver *MinimaxSolver) getDepth() int {
-	return solver.optionToInt("Search Depth", 2)
+	return solver.optionToInt("Search Depth", 4)
+}
+
+func (solver *MinimaxSolver) getContempt() int {
+	return solver.optionToInt("Contempt", 0)
+}
+
+func (solver *MinimaxSolver) getEvaluator() string {
+	if opt := solver.GetOption("Evaluator"); opt != nil {
+		return *opt
+	}
+	return "classical"
+}
+
+func (solver *MinimaxSolver) getEvalFile() string {
+	if opt := solver.GetOption(nn.EvalFileOptionName); opt != nil {
+		return *opt
+	}
+	return ""
+}
+
+// useEvalFile loads the network file given by the EvalFile option, and returns
+// true if the network changed.  The previous network is kept if the file
+// can't be loaded.
+func (solver *MinimaxSolver) useEvalFile() bool {
+	changed, err := nn.UseFile(solver.getEvalFile())
+	if err != nil {
+		log.Printf("Error loading %s: %s", nn.EvalFileOptionName, err)
+	}
+	return changed
+}
+
+func (solver *MinimaxSolver) getThreads() int {
+	return solver.optionToInt("Threads", 1)
+}
+
+func (solver *MinimaxSolver) getMultiPV() int {
+	return solver.optionToInt("MultiPV", 1)
+}
+
+func (solver *MinimaxSolver) getShowCurrLine() bool {
+	return solver.optionToBool("UCI_ShowCurrLine", false)
+}
+
+func (solver *MinimaxSolver) getPVS() bool {
+	return solver.optionToBool("Principal Variation Search", true)
+}
+
+func (solver *MinimaxSolver) getNullMove() bool {
+	return solver.optionToBool("Null Move Pruning", true)
+}
+
+func (solver *MinimaxSolver) getLateMoveReductions() bool {
+	return solver.optionToBool("Late Move Reductions", true)
+}
+
+func (solver *MinimaxSolver) getCheckExtensions() bool {
+	return solver.optionToBool("Check Extensions", true)
+}
+
+// setTechniques turns the search techniques of algo on or off as set by the
+// options.
+func (solver *MinimaxSolver) setTechniques(algo *minimaxAlgo) {
+	algo.PVS = solver.getPVS()
+	algo.NullMove = solver.getNullMove()
+	algo.LateMoveReductions = solver.getLateMoveReductions()
+	algo.CheckExtensions = solver.getCheckExtensions()
+}
+
+func (solver *MinimaxSolver) getRandomMoveOrder() bool {
+	return solver.optionToBool("Random Move Order", false)
+}
+
+// setParams applies the evaluation parameters set as options.
+func (solver *MinimaxSolver) setParams() {
+	for _, param := range utils.Params() {
+		param.Set(solver.optionToInt(param.Name, param.Default))
+	}
+}
+
+func (solver *MinimaxSolver) optionToBool(name string, def bool) bool {
+	opt := solver.GetOption(name)
+	if opt == nil {
+		return def
+	}
+	b, e := strconv.ParseBool(*opt)
+	if e != nil {
+		log.Fatalf("Error casting option %s: %s", name, e)
+	}
+	return b
 }
 
 func (solver *MinimaxSolver) optionToInt(name string, def int) int {
@@ -74,61 +177,212 @@ func (solver *MinimaxSolver) DoMove(move string) {
 	solver.base.DoMove(move)
 }
 
+// NewGame clears the transposition table, as its results are unlikely to be
+// useful in a different game.
+func (solver *MinimaxSolver) NewGame() {
+	solver.StopSearch()
+	solver.searching.Wait()
+
+	solver.base.NewGame()
+	if solver.algo != nil {
+		solver.algo.Clear()
+	}
+}
+
+// Eval traces the evaluation of the current position, with the evaluator, its
+// network file and the evaluation parameters set as options.
+func (solver *MinimaxSolver) Eval() utils.Evaluation {
+	solver.setParams()
+	if solver.useEvalFile() {
+		solver.reload = true
+	}
+	return solver.base.Eval()
+}
+
+func (solver *MinimaxSolver) Perft(depth int) []board.PerftResult {
+	return solver.base.Perft(depth)
+}
+
 func (solver *MinimaxSolver) StartSearch(sp *solver.SearchParams, moves ...string) chan []string {
+	solver.stopSearch()
+	solver.searching.Wait()
+
 	solver.base.StartMove()
 
 	ret := solver.base.GetResultCh()
 
-	go solver.minimax(sp, moves...)
-	if !sp.Infinite && !sp.Ponder {
-		var duration int
-		if movetime := sp.Movetime; movetime > 0 {
-			duration = movetime
-		} else {
-			if turn := solver.base.Game.Position().Turn(); turn == chess.White && sp.Wtime > 0 {
-				duration = sp.Wtime
-			} else if turn == chess.Black && sp.Btime > 0 {
-				duration = sp.Btime
-			}
-		}
+	tm := solver.base.NewTimeManager(sp)
 
-		if duration > 0 {
-			go func() {
-				// TODO: represent SearchParams.Movetime as time.Duration
-				time.Sleep(time.Duration(duration) * time.Millisecond)
-				solver.base.CloseMove()
-			}()
-		}
+	solver.mutex.Lock()
+	solver.timeManager = tm
+	solver.pondering = sp.Ponder
+	solver.infinite = sp.Infinite
+	solver.bounded = sp.Depth > 0 || sp.Nodes > 0 || sp.Mate > 0
+	solver.finished = false
+	solver.mutex.Unlock()
+
+	solver.setupAlgo(sp, tm)
+
+	solver.searching.Add(1)
+	go solver.minimax(moves...)
+
+	if tm.Started() {
+		solver.startTimer(tm)
 	}
 
 	return ret
 }
 
-func (solver *MinimaxSolver) minimax(sp *solver.SearchParams, moves ...string) {
+// startTimer stops the search once the hard time limit has passed.
+func (solver *MinimaxSolver) startTimer(tm *solver.TimeManager) {
+	if !tm.Limited() {
+		return
+	}
+
+	solver.mutex.Lock()
+	defer solver.mutex.Unlock()
+
+	solver.timer = time.AfterFunc(tm.HardLimit()-tm.Elapsed(), func() {
+		// ignore timers of previous searches
+		if solver.currentTimeManager() == tm {
+			solver.StopSearch()
+		}
+	})
+}
+
+func (solver *MinimaxSolver) currentTimeManager() *solver.TimeManager {
+	solver.mutex.Lock()
+	defer solver.mutex.Unlock()
+
+	return solver.timeManager
+}
+
+func (solver *MinimaxSolver) isPondering() bool {
+	solver.mutex.Lock()
+	defer solver.mutex.Unlock()
+
+	return solver.pondering
+}
+
+// setupAlgo prepares the search algorithm for the next search.  The search
+// deepens until stopped, unless limited by the "go depth", "go nodes" or "go
+// mate" parameters or, if the search has no other limit, the "Search Depth"
+// option.  A mate in n moves is found by a search of 2n-1 plies.
+func (solver *MinimaxSolver) setupAlgo(sp *solver.SearchParams, tm *solver.TimeManager) {
 	submit := func(move []string) bool {
-		if sp.Ponder {
-			return solver.base.SubmitPonderCh(move)
+		if solver.isPondering() && solver.base.SubmitPonderCh(move) {
+			return true
 		}
 		return solver.base.SubmitResultCh(move)
 	}
 
-	var depth int
-	if depthConfig := solver.getDepth(); depth < 1 || depth > depthConfig {
-		depth = depthConfig
+	solver.setParams()
+
+	depth := MaxPly
+	if sp.Mate > 0 && 2*sp.Mate-1 < depth {
+		depth = 2*sp.Mate - 1
+	}
+	if sp.Depth > 0 && sp.Depth < depth {
+		depth = sp.Depth
+	} else if sp.Depth <= 0 && sp.Mate <= 0 && sp.Nodes <= 0 &&
+		!tm.Limited() && !sp.Infinite && !sp.Ponder {
+		depth = solver.getDepth()
 	}
 
-	if hashSize := solver.getHashSize(); solver.algo == nil || solver.algo.MaxDepth != depth || solver.algo.HashSize != hashSize {
+	if hashSize := solver.getHashSize(); solver.algo == nil || solver.algo.HashSize != hashSize {
 		solver.algo = newMinimaxAlgo(depth, hashSize, submit, solver.emitter)
 	}
+	solver.algo.MaxDepth = depth
+	solver.algo.SetThreads(solver.getThreads())
+	solver.algo.Randomize = solver.getRandomMoveOrder()
+	solver.algo.MultiPV = solver.getMultiPV()
+	solver.algo.ShowCurrLine = solver.getShowCurrLine()
+	solver.setTechniques(solver.algo)
+	if sp.Mate > 0 {
+		// prove the mate to the full depth, as pruning may miss it
+		solver.algo.NullMove = false
+		solver.algo.LateMoveReductions = false
+	}
+	reload := solver.useEvalFile() || solver.reload
+	solver.algo.SetEvaluator(solver.getEvaluator(), reload)
+	solver.reload = false
+	solver.algo.MaxNodes = sp.Nodes
+	solver.algo.MateMoves = sp.Mate
+	solver.algo.Contempt = solver.getContempt()
+	solver.algo.submit = submit
+	solver.algo.timeManager = tm
+	solver.algo.Reset()
+}
 
-	solver.algo.Start(solver.base.Game.Position(), solver.base.GetValidMoves(moves...)...)
-	solver.base.CloseMove()
+func (solver *MinimaxSolver) minimax(moves ...string) {
+	defer solver.searching.Done()
+
+	position := solver.base.Position()
+	solver.algo.Start(position, rootMoves(position, moves...)...)
+
+	solver.mutex.Lock()
+	solver.finished = true
+	// in infinite and ponder mode the result is only sent once told so
+	wait := solver.infinite || solver.pondering
+	solver.mutex.Unlock()
+
+	if !wait {
+		solver.base.CloseMove()
+	}
+}
+
+// rootMoves returns the legal moves of moves, given in long algebraic
+// notation, to restrict the search to.
+func rootMoves(position *board.Position, moves ...string) []board.Move {
+	ret := make([]board.Move, 0, len(moves))
+	for _, m := range moves {
+		if move, err := position.ParseMove(m); err == nil {
+			ret = append(ret, move)
+		}
+	}
+	return ret
+}
+
+func (solver *MinimaxSolver) stopSearch() {
+	solver.mutex.Lock()
+	if solver.timer != nil {
+		solver.timer.Stop()
+		solver.timer = nil
+	}
+	solver.mutex.Unlock()
+
+	if solver.algo != nil {
+		solver.algo.Stop()
+	}
 }
 
 func (solver *MinimaxSolver) StopSearch() {
+	solver.stopSearch()
 	solver.base.CloseMove()
 }
 
+// PonderHit switches a ponder search to a normal search, starting the clock
+// now that it's the engine's turn.
 func (solver *MinimaxSolver) PonderHit() {
-	solver.base.PonderHit()
+	solver.mutex.Lock()
+	if !solver.pondering {
+		solver.mutex.Unlock()
+		return
+	}
+	solver.pondering = false
+	// move the ponder result across before minimax can see the search as no
+	// longer pondering and close the move
+	solver.base.StopPondering()
+	finished, bounded, tm := solver.finished, solver.bounded, solver.timeManager
+	solver.mutex.Unlock()
+
+	tm.Start()
+
+	// without a time, depth, node or mate limit the search would never end,
+	// so return the best move found so far
+	if finished || !tm.Limited() && !bounded {
+		solver.StopSearch()
+		return
+	}
+	solver.startTimer(tm)
 }
@@ -0,0 +1,169 @@
+package minimax
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+// bound describes how a score stored in the transposition table relates to the
+// true score of the position.
+type bound uint8
+
+const (
+	boundNone  bound = iota
+	boundExact       // the score is exact
+	boundLower       // the true score is at least the stored score
+	boundUpper       // the true score is at most the stored score
+)
+
+// ttMove is a compact encoding of a board.Move: origin and destination squares
+// and promotion piece type.
+type ttMove uint16
+
+const noTTMove ttMove = 0
+
+func newTTMove(move board.Move) ttMove {
+	return ttMove(move.From()) | ttMove(move.To())<<6 | ttMove(move.Promo())<<12
+}
+
+// Matches returns true if move is the move encoded by m.
+func (m ttMove) Matches(move board.Move) bool {
+	return m != noTTMove && m == newTTMove(move)
+}
+
+type ttEntry struct {
+	key   uint64
+	score int32 // from White's point of view
+	move  ttMove
+	depth int8
+	bound bound // from White's point of view
+	age   uint8
+}
+
+// pack encodes entry, except for the key, in 64 bits.  Only the low 6 bits of
+// the age are kept.
+func (entry ttEntry) pack() uint64 {
+	return uint64(uint32(entry.score)) | uint64(entry.move)<<32 | uint64(uint8(entry.depth))<<48 |
+		uint64(entry.bound)<<56 | uint64(entry.age&ageMask)<<58
+}
+
+func unpack(key, data uint64) ttEntry {
+	return ttEntry{
+		key:   key,
+		score: int32(uint32(data)),
+		move:  ttMove(data >> 32),
+		depth: int8(data >> 48),
+		bound: bound(data>>56) & 3,
+		age:   uint8(data>>58) & ageMask}
+}
+
+const ageMask = 1<<6 - 1
+
+// ttSlot stores an entry as its packed data, and its key XORed with the data.
+// Both are accessed atomically, so that search threads can share the table
+// without locking: an entry torn by concurrent writes no longer matches its key
+// and is ignored.
+type ttSlot struct {
+	check uint64
+	data  uint64
+}
+
+func (slot *ttSlot) load() ttEntry {
+	data := atomic.LoadUint64(&slot.data)
+	return unpack(atomic.LoadUint64(&slot.check)^data, data)
+}
+
+func (slot *ttSlot) store(entry ttEntry) {
+	data := entry.pack()
+	atomic.StoreUint64(&slot.data, data)
+	atomic.StoreUint64(&slot.check, entry.key^data)
+}
+
+const ttEntrySize = int(unsafe.Sizeof(ttSlot{}))
+
+// transpositionTable is a fixed-size hash table of search results, indexed by
+// Zobrist key, and shared by the search threads.  An entry is replaced by a
+// result of the current search unless the stored result was searched deeper in
+// the current search.
+type transpositionTable struct {
+	entries []ttSlot
+	age     uint8
+}
+
+func newTranspositionTable(sizeMB int) *transpositionTable {
+	if sizeMB < 1 {
+		sizeMB = 1
+	}
+	return &transpositionTable{
+		entries: make([]ttSlot, sizeMB*1024*1024/ttEntrySize)}
+}
+
+func (tt *transpositionTable) index(key uint64) uint64 {
+	// maps the key uniformly to [0, len(entries)) without a division
+	hi, _ := bits.Mul64(key, uint64(len(tt.entries)))
+	return hi
+}
+
+// Probe returns the entry stored for key, if any.
+func (tt *transpositionTable) Probe(key uint64) (ttEntry, bool) {
+	entry := tt.entries[tt.index(key)].load()
+	return entry, entry.bound != boundNone && entry.key == key
+}
+
+// Store saves a search result for key.
+func (tt *transpositionTable) Store(key uint64, depth int, score utils.CentiPawns,
+	b bound, move board.Move) {
+
+	slot := &tt.entries[tt.index(key)]
+	entry := slot.load()
+	if entry.age == tt.age&ageMask && entry.key != key && int(entry.depth) > depth {
+		return
+	}
+
+	// keep the best move of a previous search of this position
+	m := newTTMove(move)
+	if m == noTTMove && entry.key == key {
+		m = entry.move
+	}
+
+	slot.store(ttEntry{key, int32(score), m, int8(depth), b, tt.age})
+}
+
+// NewSearch ages the table, so entries of previous searches are replaced first.
+// Only 6 bits of the age are stored, so once it wraps the table is cleared,
+// rather than entries 64 searches old passing for current ones.
+func (tt *transpositionTable) NewSearch() {
+	tt.age = (tt.age + 1) & ageMask
+	if tt.age == 0 {
+		tt.Clear()
+	}
+}
+
+// Clear removes all entries.
+func (tt *transpositionTable) Clear() {
+	for i := range tt.entries {
+		tt.entries[i].store(ttEntry{})
+	}
+	tt.age = 0
+}
+
+// Hashfull returns the permill of entries used by the current search,
+// estimated from a sample of the table.
+func (tt *transpositionTable) Hashfull() int {
+	sample := 1000
+	if len(tt.entries) < sample {
+		sample = len(tt.entries)
+	}
+
+	used := 0
+	for i := range tt.entries[:sample] {
+		if entry := tt.entries[i].load(); entry.bound != boundNone && entry.age == tt.age&ageMask {
+			used++
+		}
+	}
+	return used * 1000 / sample
+}
@@ -0,0 +1,109 @@
+package minimax
+
+import (
+	"github.com/mhv2109/uci-impl/internal/board"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TranspositionTable", func() {
+	var (
+		tt   *transpositionTable
+		move board.Move
+	)
+
+	BeforeEach(func() {
+		tt = newTranspositionTable(1)
+		move = board.NewPosition().LegalMoves(nil)[0]
+	})
+
+	It("Is sized in MB", func() {
+		Expect(len(tt.entries) * ttEntrySize).
+			To(BeNumerically("~", 1024*1024, ttEntrySize))
+	})
+
+	It("Returns stored entries", func() {
+		tt.Store(42, 3, 150, boundLower, move)
+
+		entry, ok := tt.Probe(42)
+		Expect(ok).
+			To(BeTrue())
+		Expect(entry.score).
+			To(BeEquivalentTo(150))
+		Expect(entry.depth).
+			To(BeEquivalentTo(3))
+		Expect(entry.bound).
+			To(Equal(boundLower))
+		Expect(entry.move.Matches(move)).
+			To(BeTrue())
+	})
+
+	It("Ignores entries torn by concurrent writes", func() {
+		tt.Store(42, 3, 150, boundExact, move)
+		tt.entries[tt.index(42)].data ^= 1
+
+		_, ok := tt.Probe(42)
+		Expect(ok).
+			To(BeFalse())
+	})
+
+	It("Misses unknown keys", func() {
+		_, ok := tt.Probe(42)
+		Expect(ok).
+			To(BeFalse())
+	})
+
+	It("Keeps deeper entries of the current search", func() {
+		other := uint64(43)
+		Expect(tt.index(other)).
+			To(Equal(tt.index(42)))
+
+		tt.Store(42, 5, 150, boundExact, move)
+		tt.Store(other, 1, 0, boundExact, board.NoMove)
+
+		_, ok := tt.Probe(42)
+		Expect(ok).
+			To(BeTrue())
+
+		tt.NewSearch()
+		tt.Store(other, 1, 0, boundExact, board.NoMove)
+
+		_, ok = tt.Probe(42)
+		Expect(ok).
+			To(BeFalse())
+	})
+
+	It("Doesn't keep entries of a search as old as the age wraps", func() {
+		other := uint64(43)
+		tt.Store(42, 5, 150, boundExact, move)
+		for i := 0; i <= ageMask; i++ {
+			tt.NewSearch()
+		}
+		tt.Store(other, 1, 0, boundExact, board.NoMove)
+
+		_, ok := tt.Probe(other)
+		Expect(ok).
+			To(BeTrue())
+	})
+
+	It("Reports hashfull for the current search", func() {
+		for key := uint64(0); key < 1<<16; key++ {
+			tt.Store(key*0x9E3779B97F4A7C15, 1, 0, boundExact, board.NoMove)
+		}
+		Expect(tt.Hashfull()).
+			To(BeNumerically(">", 0))
+
+		tt.NewSearch()
+		Expect(tt.Hashfull()).
+			To(Equal(0))
+	})
+
+	It("Clears all entries", func() {
+		tt.Store(42, 3, 150, boundExact, move)
+		tt.Clear()
+
+		_, ok := tt.Probe(42)
+		Expect(ok).
+			To(BeFalse())
+	})
+})
@@ -0,0 +1,36 @@
+package nn
+
+import (
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+// defaultOffset keeps the hidden units of the default network active, so
+// they're linear.  It's larger than any material and piece-square sum.
+const defaultOffset = 20000
+
+// defaultNetwork is built into the binary and used unless a network file is
+// given.  It has two hidden units that sum the classical material and
+// middlegame and endgame piece-square values, averaged by the output, as a
+// baseline for trained networks.
+var defaultNetwork = newDefaultNetwork()
+
+func newDefaultNetwork() *Network {
+	network := NewNetwork(2)
+	for piece := board.WhiteKing; piece <= board.BlackPawn; piece++ {
+		sign := float32(1)
+		if piece.Color() == board.Black {
+			sign = -1
+		}
+		for sq := board.A1; sq <= board.H8; sq++ {
+			s := utils.PieceSquareScore(piece, sq)
+			f := feature(piece, sq)
+			network.InputWeights[f*2] = sign * float32(s.MG)
+			network.InputWeights[f*2+1] = sign * float32(s.EG)
+		}
+	}
+	network.HiddenBiases[0], network.HiddenBiases[1] = defaultOffset, defaultOffset
+	network.OutputWeights[0], network.OutputWeights[1] = 0.5, 0.5
+	network.OutputBias = -defaultOffset
+	return network
+}
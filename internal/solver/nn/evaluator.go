@@ -0,0 +1,151 @@
+package nn
+
+import (
+	"sync"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+// EvaluatorName is the name the network evaluator is registered as.
+const EvaluatorName = "network"
+
+// EvalFileOptionName is the name of the Option that gives the network file.
+const EvalFileOptionName = "EvalFile"
+
+// emptyFile is the UCI value of an empty string option, which selects the
+// default network.
+const emptyFile = "<empty>"
+
+var (
+	mutex       sync.Mutex
+	network     = defaultNetwork
+	networkFile = ""
+)
+
+func init() {
+	solver.RegisterEvaluator(EvaluatorName, func() solver.Evaluator {
+		return NewEvaluator(currentNetwork())
+	})
+}
+
+// NewEvalFileOption returns the Option that gives the network file, or the
+// default network if empty.
+func NewEvalFileOption() *solver.Option {
+	return &solver.Option{
+		Name:    EvalFileOptionName,
+		Type:    solver.OptionStringType,
+		Default: emptyFile}
+}
+
+// UseFile loads the network file at path for evaluators created from now on,
+// or the default network if path is empty.  It returns true if the network
+// changed.  On error the network is left unchanged.
+func UseFile(path string) (bool, error) {
+	if path == emptyFile {
+		path = ""
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if path == networkFile {
+		return false, nil
+	}
+
+	next := defaultNetwork
+	if path != "" {
+		var err error
+		if next, err = Load(path); err != nil {
+			return false, err
+		}
+	}
+	network, networkFile = next, path
+	return true, nil
+}
+
+func currentNetwork() *Network {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return network
+}
+
+// Evaluator evaluates positions with a Network, updating the hidden layer
+// incrementally as moves are made.
+type Evaluator struct {
+	network      *Network
+	accumulators [][]float32 // a stack, one for each move made
+	ply          int
+}
+
+// NewEvaluator returns an Evaluator using network.
+func NewEvaluator(network *Network) *Evaluator {
+	return &Evaluator{network, [][]float32{make([]float32, network.Hidden)}, 0}
+}
+
+func (e *Evaluator) Evaluate(position *board.Position) utils.CentiPawns {
+	return e.network.Evaluate(position)
+}
+
+// Trace returns the output of the network as a single term, as it can't be
+// broken down.
+func (e *Evaluator) Trace(position *board.Position) utils.Evaluation {
+	var evaluation utils.Evaluation
+	evaluation.Phase = utils.MaxPhase
+	score := e.Evaluate(position)
+	evaluation.Add(utils.NetworkTerm, board.White, utils.Score{MG: score, EG: score})
+	return evaluation
+}
+
+func (e *Evaluator) Reset(position *board.Position) {
+	e.ply = 0
+	e.network.Refresh(e.accumulators[0], position)
+}
+
+func (e *Evaluator) MakeMove(position *board.Position, move board.Move) {
+	if e.ply+1 == len(e.accumulators) {
+		e.accumulators = append(e.accumulators, make([]float32, e.network.Hidden))
+	}
+	accumulator := e.accumulators[e.ply+1]
+	copy(accumulator, e.accumulators[e.ply])
+	e.ply++
+
+	from, to := move.From(), move.To()
+	piece := position.Piece(from)
+
+	e.network.sub(accumulator, feature(piece, from))
+	if promo := move.Promo(); promo != board.NoPieceType {
+		e.network.add(accumulator, feature(board.NewPiece(piece.Color(), promo), to))
+	} else {
+		e.network.add(accumulator, feature(piece, to))
+	}
+
+	if move.IsEnPassant() {
+		// the captured pawn is beside the moving one
+		sq := board.NewSquare(to.File(), from.Rank())
+		e.network.sub(accumulator, feature(position.Piece(sq), sq))
+	} else if captured := position.Piece(to); captured != board.NoPiece {
+		e.network.sub(accumulator, feature(captured, to))
+	}
+
+	if move.IsCastle() {
+		rank := from.Rank()
+		rookFrom, rookTo := board.NewSquare(7, rank), board.NewSquare(5, rank)
+		if to.File() < from.File() {
+			rookFrom, rookTo = board.NewSquare(0, rank), board.NewSquare(3, rank)
+		}
+		rook := position.Piece(rookFrom)
+		e.network.sub(accumulator, feature(rook, rookFrom))
+		e.network.add(accumulator, feature(rook, rookTo))
+	}
+}
+
+func (e *Evaluator) UnmakeMove() {
+	e.ply--
+}
+
+func (e *Evaluator) Current() utils.CentiPawns {
+	return e.network.Output(e.accumulators[e.ply])
+}
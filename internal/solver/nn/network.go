@@ -0,0 +1,145 @@
+// Package nn implements a small neural network evaluator: a feed-forward
+// network with one hidden layer over piece-square input features.
+package nn
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+// Inputs is the number of input features, one for each piece on each square.
+const Inputs = 12 * 64
+
+// magic identifies network files.
+var magic = [4]byte{'U', 'N', 'N', '1'}
+
+// maxHidden bounds the hidden layer of network files, to reject corrupt ones.
+const maxHidden = 4096
+
+// Network is a feed-forward network with a ReLU hidden layer, evaluating
+// positions in centipawns from White's point of view.  Networks are immutable
+// once created, so they can be shared between evaluators.
+type Network struct {
+	Hidden        int
+	InputWeights  []float32 // Inputs x Hidden, by feature
+	HiddenBiases  []float32 // Hidden
+	OutputWeights []float32 // Hidden
+	OutputBias    float32
+}
+
+// NewNetwork returns a network with all weights zero.
+func NewNetwork(hidden int) *Network {
+	return &Network{
+		Hidden:        hidden,
+		InputWeights:  make([]float32, Inputs*hidden),
+		HiddenBiases:  make([]float32, hidden),
+		OutputWeights: make([]float32, hidden)}
+}
+
+// feature returns the input feature of piece on sq.
+func feature(piece board.Piece, sq board.Square) int {
+	return (int(piece)-1)*64 + int(sq)
+}
+
+// Output returns the output of the network for the hidden layer
+// pre-activations in accumulator.
+func (network *Network) Output(accumulator []float32) utils.CentiPawns {
+	out := network.OutputBias
+	for i, v := range accumulator {
+		if v > 0 {
+			out += v * network.OutputWeights[i]
+		}
+	}
+	return utils.CentiPawns(math.Round(float64(out)))
+}
+
+// Refresh sets accumulator to the hidden layer pre-activations of position.
+func (network *Network) Refresh(accumulator []float32, position *board.Position) {
+	copy(accumulator, network.HiddenBiases)
+	for occupied := position.AllOccupied(); occupied != 0; {
+		sq := occupied.Pop()
+		network.add(accumulator, feature(position.Piece(sq), sq))
+	}
+}
+
+func (network *Network) add(accumulator []float32, f int) {
+	weights := network.InputWeights[f*network.Hidden : (f+1)*network.Hidden]
+	for i, w := range weights {
+		accumulator[i] += w
+	}
+}
+
+func (network *Network) sub(accumulator []float32, f int) {
+	weights := network.InputWeights[f*network.Hidden : (f+1)*network.Hidden]
+	for i, w := range weights {
+		accumulator[i] -= w
+	}
+}
+
+// Evaluate evaluates position from scratch.
+func (network *Network) Evaluate(position *board.Position) utils.CentiPawns {
+	accumulator := make([]float32, network.Hidden)
+	network.Refresh(accumulator, position)
+	return network.Output(accumulator)
+}
+
+// Write writes network in the format read by Read: the magic "UNN1", the
+// size of the hidden layer as a uint32, then the input weights, hidden biases,
+// output weights and output bias as float32s, all little-endian.
+func (network *Network) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, v := range []interface{}{magic, uint32(network.Hidden), network.InputWeights,
+		network.HiddenBiases, network.OutputWeights, network.OutputBias} {
+		if err := binary.Write(bw, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Read reads a network written by Write.
+func Read(r io.Reader) (*Network, error) {
+	br := bufio.NewReader(r)
+
+	var header struct {
+		Magic  [4]byte
+		Hidden uint32
+	}
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Magic != magic {
+		return nil, errors.New("not a network file")
+	}
+	if header.Hidden == 0 || header.Hidden > maxHidden {
+		return nil, fmt.Errorf("invalid hidden layer size %d", header.Hidden)
+	}
+
+	network := NewNetwork(int(header.Hidden))
+	for _, v := range []interface{}{network.InputWeights, network.HiddenBiases,
+		network.OutputWeights, &network.OutputBias} {
+		if err := binary.Read(br, binary.LittleEndian, v); err != nil {
+			return nil, err
+		}
+	}
+	return network, nil
+}
+
+// Load reads a network from the file at path.
+func Load(path string) (*Network, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
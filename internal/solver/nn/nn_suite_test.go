@@ -0,0 +1,13 @@
+package nn_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestNN(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "NN Suite")
+}
@@ -0,0 +1,185 @@
+package nn_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver"
+	. "github.com/mhv2109/uci-impl/internal/solver/nn"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+const startFEN = board.StartFEN
+
+func position(fen string) *board.Position {
+	pos, err := board.ParseFEN(fen)
+	Expect(err).
+		ToNot(HaveOccurred())
+	return pos
+}
+
+func testNetwork() *Network {
+	network := NewNetwork(4)
+	for i := range network.InputWeights {
+		network.InputWeights[i] = float32(i%13) - 6
+	}
+	for i := range network.HiddenBiases {
+		network.HiddenBiases[i] = float32(i * 10)
+	}
+	for i := range network.OutputWeights {
+		network.OutputWeights[i] = 0.25 * float32(i+1)
+	}
+	network.OutputBias = 3
+	return network
+}
+
+var _ = Describe("Network", func() {
+	It("Reads back what it writes", func() {
+		network := testNetwork()
+
+		var buf bytes.Buffer
+		Expect(network.Write(&buf)).
+			To(Succeed())
+		read, err := Read(&buf)
+		Expect(err).
+			ToNot(HaveOccurred())
+		Expect(read).
+			To(Equal(network))
+	})
+
+	It("Rejects files that aren't networks", func() {
+		_, err := Read(bytes.NewBufferString("not a network"))
+		Expect(err).
+			To(HaveOccurred())
+	})
+
+	It("Averages the classical middlegame and endgame piece-square values by default", func() {
+		evaluator := solver.NewEvaluator(EvaluatorName)
+		pos := position("r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4")
+
+		var sum utils.Score
+		for occupied := pos.AllOccupied(); occupied != 0; {
+			sq := occupied.Pop()
+			piece := pos.Piece(sq)
+			s := utils.PieceSquareScore(piece, sq)
+			if piece.Color() == board.Black {
+				sum = sum.Sub(s)
+			} else {
+				sum = sum.Add(s)
+			}
+		}
+		Expect(evaluator.Evaluate(pos)).
+			To(BeNumerically("~", (sum.MG+sum.EG)/2, 1))
+	})
+})
+
+var _ = Describe("Evaluator", func() {
+	var evaluator *Evaluator
+
+	BeforeEach(func() {
+		evaluator = NewEvaluator(testNetwork())
+	})
+
+	// expectIncremental plays moves from fen and takes them back, checking the
+	// evaluation matches the evaluation from scratch after each.
+	expectIncremental := func(fen string, moves ...string) {
+		pos := position(fen)
+		evaluator.Reset(pos)
+
+		for _, m := range moves {
+			move, err := pos.ParseMove(m)
+			Expect(err).
+				ToNot(HaveOccurred())
+			evaluator.MakeMove(pos, move)
+			pos.MakeMove(move)
+
+			Expect(evaluator.Current()).
+				To(Equal(evaluator.Evaluate(pos)))
+		}
+		for range moves {
+			pos.UnmakeMove()
+			evaluator.UnmakeMove()
+			Expect(evaluator.Current()).
+				To(Equal(evaluator.Evaluate(pos)))
+		}
+	}
+
+	It("Updates incrementally after quiet moves and captures", func() {
+		expectIncremental(startFEN, "e2e4", "d7d5", "e4d5", "d8d5")
+	})
+
+	It("Updates incrementally after castling", func() {
+		expectIncremental("r3k2r/pppqbppp/2np1n2/4p3/4P3/2NP1N2/PPPQBPPP/R3K2R w KQkq - 0 1", "e1g1", "e8c8")
+	})
+
+	It("Updates incrementally after en passant", func() {
+		expectIncremental("4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "e5d6")
+	})
+
+	It("Updates incrementally after promotions", func() {
+		expectIncremental("1n2k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a7b8q")
+	})
+
+	It("Reports its output as a single network term", func() {
+		pos := position(startFEN)
+		evaluation := evaluator.Trace(pos)
+		Expect(evaluation.Total()).
+			To(Equal(evaluator.Evaluate(pos)))
+		Expect(evaluation.String()).
+			To(ContainSubstring(utils.NetworkTerm.String()))
+	})
+})
+
+var _ = Describe("UseFile", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "nn")
+		Expect(err).
+			ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		UseFile("")
+		os.RemoveAll(dir)
+	})
+
+	It("Loads networks for new evaluators", func() {
+		network := testNetwork()
+		path := filepath.Join(dir, "test.nn")
+		f, err := os.Create(path)
+		Expect(err).
+			ToNot(HaveOccurred())
+		Expect(network.Write(f)).
+			To(Succeed())
+		Expect(f.Close()).
+			To(Succeed())
+
+		Expect(UseFile(path)).
+			To(BeTrue())
+		Expect(UseFile(path)).
+			To(BeFalse())
+
+		pos := position(startFEN)
+		Expect(solver.NewEvaluator(EvaluatorName).Evaluate(pos)).
+			To(Equal(network.Evaluate(pos)))
+
+		Expect(UseFile("<empty>")).
+			To(BeTrue())
+	})
+
+	It("Keeps the network if the file can't be loaded", func() {
+		changed, err := UseFile(filepath.Join(dir, "missing.nn"))
+		Expect(err).
+			To(HaveOccurred())
+		Expect(changed).
+			To(BeFalse())
+	})
+})
@@ -43,9 +43,22 @@ type Option struct {
 	Vars    []string
 }
 
-// NewOptions returns a new instance of Options.
+// NewOptions returns a new instance of Options.  Option names are case
+// insensitive, as the GUI may send them in any case.
 func NewOptions() Options {
-	return config.NewConfiguration()
+	return caseInsensitiveOptions{config.NewConfiguration()}
+}
+
+type caseInsensitiveOptions struct {
+	config config.Configuration
+}
+
+func (options caseInsensitiveOptions) Get(key string) *string {
+	return options.config.Get(strings.ToLower(key))
+}
+
+func (options caseInsensitiveOptions) Set(key, value string) {
+	options.config.Set(strings.ToLower(key), value)
 }
 
 func (o *Option) String() string {
@@ -5,7 +5,7 @@ import (
 )
 
 func availableOptions() []*solver.Option {
-	options := make([]*solver.Option, 1, 1)
+	options := make([]*solver.Option, 2, 2)
 
 	UCI_EngineAboutOption := &solver.Option{
 		Name:    "UCI_EngineAboutOption",
@@ -13,6 +13,7 @@ func availableOptions() []*solver.Option {
 		Default: "A UCI Chess engine, written in Go by mhv2109, that chooses a valid move at random"}
 
 	options[0] = UCI_EngineAboutOption
+	options[1] = solver.NewMoveOverheadOption()
 
 	return options
 }
@@ -5,11 +5,14 @@ import (
 
 	"github.com/notnil/chess"
 
+	"github.com/mhv2109/uci-impl/internal/board"
 	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
 )
 
 type RandomSolver struct {
-	base *solver.AbstractSolver
+	base        *solver.AbstractSolver
+	timeManager *solver.TimeManager // of the current search
 }
 
 func NewRandomSolver() solver.Solver {
@@ -41,8 +44,28 @@ func (solver *RandomSolver) DoMove(move string) {
 	solver.base.DoMove(move)
 }
 
+func (solver *RandomSolver) NewGame() {
+	solver.base.NewGame()
+}
+
+func (solver *RandomSolver) Eval() utils.Evaluation {
+	return solver.base.Eval()
+}
+
+func (solver *RandomSolver) Perft(depth int) []board.PerftResult {
+	return solver.base.Perft(depth)
+}
+
+// Bench searches nothing, as moves are picked at random.
+func (solver *RandomSolver) Bench(depth int) (result solver.BenchResult) {
+	return
+}
+
+// StartSearch picks a move at random as soon as it's asked, well within the
+// budget of its TimeManager.
 func (solver *RandomSolver) StartSearch(sp *solver.SearchParams, moves ...string) chan []string {
 	solver.base.StartMove()
+	solver.timeManager = solver.base.NewTimeManager(sp)
 
 	ret := solver.base.GetResultCh()
 
@@ -69,6 +92,10 @@ func (solver *RandomSolver) StopSearch() {
 	solver.base.CloseMove()
 }
 
+// PonderHit starts the clock of the ponder search, and returns its move.
 func (solver *RandomSolver) PonderHit() {
+	if solver.timeManager != nil {
+		solver.timeManager.Start()
+	}
 	solver.base.PonderHit()
 }
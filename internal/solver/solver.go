@@ -3,10 +3,21 @@ package solver
 import (
 	"log"
 	"sync"
+	"time"
 
 	"github.com/notnil/chess"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
 )
 
+// BenchResult is the outcome of searching the bench positions of a Solver.
+type BenchResult struct {
+	Positions int           // positions searched
+	Nodes     int           // nodes searched in total
+	Elapsed   time.Duration // time taken in total
+}
+
 // SearchParams is a struct that holds values for commands that follow the "Go"
 // command in the UCI protocol.
 type SearchParams struct {
@@ -52,6 +63,10 @@ type Solver interface {
 	SetPosition(string, ...string) // set game position with FEN string & individual moves in Long-Algebraic format
 	SetStartPosition(...string)    // set game position at "start", plus individual moves in Long-Algebraic format
 	DoMove(string)                 // do an individual move in Long-Algebraic format
+	NewGame()                      // reset any state kept between searches, as the next search is from a different game
+	Eval() utils.Evaluation        // evaluate the current position statically, broken down by term
+	Perft(int) []board.PerftResult // count the leaf nodes of the move tree of the current position by root move
+	Bench(int) BenchResult         // search a fixed set of positions to depth, or a default depth if 0, to fingerprint the search
 	// Start searching asynchronously, and put results on the returned channel.
 	// The search algorithm can place the "best current move" on the channel
 	// as they are found.  When StopSearch is called, or the time limit
@@ -124,6 +139,45 @@ func (solver *AbstractSolver) SetStartPosition(moves ...string) {
 	solver.doMoves(moves...)
 }
 
+// NewGame resets the Game to the start position, as the next search is from a
+// different game.
+func (solver *AbstractSolver) NewGame() {
+	solver.SetStartPosition()
+}
+
+// Eval returns the static evaluation of the current position by the selected
+// Evaluator, broken down by term.
+func (solver *AbstractSolver) Eval() utils.Evaluation {
+	name := DefaultEvaluatorName
+	if opt := solver.GetOption(EvaluatorOptionName); opt != nil {
+		name = *opt
+	}
+	return NewEvaluator(name).Trace(solver.Position())
+}
+
+// Perft returns the perft count to depth below each legal move of the current
+// position.
+func (solver *AbstractSolver) Perft(depth int) []board.PerftResult {
+	return solver.Position().Divide(depth)
+}
+
+// Position returns the current position of the Game as a board.Position, with
+// the moves of the Game made so repetitions of earlier positions are detected.
+func (solver *AbstractSolver) Position() *board.Position {
+	position, err := board.ParseFEN(solver.Game.Positions()[0].String())
+	if err != nil {
+		log.Panicln(err)
+	}
+	for _, m := range solver.Game.Moves() {
+		move, err := position.ParseMove(m.String())
+		if err != nil {
+			log.Panicln(err)
+		}
+		position.MakeMove(move)
+	}
+	return position
+}
+
 // GetValidMoves returns all valid moves for the current Game state.
 func (solver *AbstractSolver) GetValidMoves(moves ...string) []*chess.Move {
 	if len(moves) == 0 {
@@ -167,8 +221,10 @@ func (solver *AbstractSolver) setupPonderCh() {
 	solver.ponderChMutex.Lock()
 	defer solver.ponderChMutex.Unlock()
 
+	// nothing reads the ponder channel before "ponderhit", so it only holds
+	// the latest result, see SubmitPonderCh
 	if solver.ponderCh == nil {
-		solver.ponderCh = make(chan []string, len(solver.Game.ValidMoves()))
+		solver.ponderCh = make(chan []string, 1)
 	}
 }
 
@@ -210,13 +266,21 @@ func (solver *AbstractSolver) SubmitResultCh(move []string) bool {
 	return false
 }
 
-// SubmitPonderCh submits search result to ponder channel.  Returns true if move was successfully subitted, false otherwise.
+// SubmitPonderCh submits search result to ponder channel, replacing a result
+// that hasn't been read yet.  Returns true if move was successfully subitted, false otherwise.
 func (solver *AbstractSolver) SubmitPonderCh(move []string) bool {
 	solver.ponderChMutex.RLock()
 	defer solver.ponderChMutex.RUnlock()
 
 	if solver.ponderCh != nil {
-		solver.ponderCh <- move
+		select {
+		case <-solver.ponderCh:
+		default:
+		}
+		select {
+		case solver.ponderCh <- move:
+		default:
+		}
 		return true
 	}
 	return false
@@ -247,6 +311,32 @@ func (solver *AbstractSolver) PonderHit() {
 	solver.closeResultCh()
 }
 
+// StopPondering ends pondering mode without ending the search, for solvers
+// that keep searching after "ponderhit".  The latest ponder result is moved to
+// the result channel, and the ponder channel is torn down so that later results
+// must be submitted using SubmitResultCh.
+func (solver *AbstractSolver) StopPondering() {
+	solver.ponderChMutex.Lock()
+	ponderCh := solver.ponderCh
+	if ponderCh != nil {
+		close(ponderCh)
+		solver.ponderCh = nil
+	}
+	solver.ponderChMutex.Unlock()
+
+	if ponderCh == nil {
+		return
+	}
+
+	var result []string
+	for move := range ponderCh {
+		result = move
+	}
+	if result != nil {
+		solver.SubmitResultCh(result)
+	}
+}
+
 // GetResultCh returns the final result channel to share with UCIHandler.
 func (solver *AbstractSolver) GetResultCh() chan []string {
 	return solver.resultCh
@@ -35,3 +35,32 @@ var _ = Describe("Option", func() {
 			To(Equal("option name testoption type string default testdefault"))
 	})
 })
+
+var _ = Describe("Options", func() {
+	It("Option names are case insensitive", func() {
+		options := NewOptions()
+		options.Set("Move Overhead", "10")
+
+		Expect(*options.Get("move overhead")).
+			To(Equal("10"))
+	})
+})
+
+var _ = Describe("AbstractSolver", func() {
+	It("Keeps only the latest ponder result, however many are submitted", func() {
+		abstractSolver := NewAbstractSolver(NewOptions())
+		abstractSolver.SetPosition("7k/8/8/8/8/8/8/K6R b - - 0 1")
+		Expect(abstractSolver.Game.ValidMoves()).
+			To(HaveLen(2))
+
+		abstractSolver.StartMove()
+		for _, move := range []string{"h8g7", "h8g8", "h8g7", "h8g8"} {
+			Expect(abstractSolver.SubmitPonderCh([]string{move})).
+				To(BeTrue())
+		}
+
+		abstractSolver.StopPondering()
+		Expect(abstractSolver.GetResultCh()).
+			To(Receive(Equal([]string{"h8g8"})))
+	})
+})
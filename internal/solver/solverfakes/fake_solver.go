@@ -4,15 +4,38 @@ package solverfakes
 import (
 	"sync"
 
+	"github.com/mhv2109/uci-impl/internal/board"
 	"github.com/mhv2109/uci-impl/internal/solver"
+	"github.com/mhv2109/uci-impl/internal/solver/utils"
 )
 
 type FakeSolver struct {
+	BenchStub        func(int) solver.BenchResult
+	benchMutex       sync.RWMutex
+	benchArgsForCall []struct {
+		arg1 int
+	}
+	benchReturns struct {
+		result1 solver.BenchResult
+	}
+	benchReturnsOnCall map[int]struct {
+		result1 solver.BenchResult
+	}
 	DoMoveStub        func(string)
 	doMoveMutex       sync.RWMutex
 	doMoveArgsForCall []struct {
 		arg1 string
 	}
+	EvalStub        func() utils.Evaluation
+	evalMutex       sync.RWMutex
+	evalArgsForCall []struct {
+	}
+	evalReturns struct {
+		result1 utils.Evaluation
+	}
+	evalReturnsOnCall map[int]struct {
+		result1 utils.Evaluation
+	}
 	GetOptionStub        func(string) *string
 	getOptionMutex       sync.RWMutex
 	getOptionArgsForCall []struct {
@@ -34,6 +57,21 @@ type FakeSolver struct {
 	getOptionsReturnsOnCall map[int]struct {
 		result1 []*solver.Option
 	}
+	NewGameStub        func()
+	newGameMutex       sync.RWMutex
+	newGameArgsForCall []struct {
+	}
+	PerftStub        func(int) []board.PerftResult
+	perftMutex       sync.RWMutex
+	perftArgsForCall []struct {
+		arg1 int
+	}
+	perftReturns struct {
+		result1 []board.PerftResult
+	}
+	perftReturnsOnCall map[int]struct {
+		result1 []board.PerftResult
+	}
 	PonderHitStub        func()
 	ponderHitMutex       sync.RWMutex
 	ponderHitArgsForCall []struct {
@@ -75,6 +113,66 @@ type FakeSolver struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeSolver) Bench(arg1 int) solver.BenchResult {
+	fake.benchMutex.Lock()
+	ret, specificReturn := fake.benchReturnsOnCall[len(fake.benchArgsForCall)]
+	fake.benchArgsForCall = append(fake.benchArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("Bench", []interface{}{arg1})
+	fake.benchMutex.Unlock()
+	if fake.BenchStub != nil {
+		return fake.BenchStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.benchReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeSolver) BenchCallCount() int {
+	fake.benchMutex.RLock()
+	defer fake.benchMutex.RUnlock()
+	return len(fake.benchArgsForCall)
+}
+
+func (fake *FakeSolver) BenchCalls(stub func(int) solver.BenchResult) {
+	fake.benchMutex.Lock()
+	defer fake.benchMutex.Unlock()
+	fake.BenchStub = stub
+}
+
+func (fake *FakeSolver) BenchArgsForCall(i int) int {
+	fake.benchMutex.RLock()
+	defer fake.benchMutex.RUnlock()
+	argsForCall := fake.benchArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSolver) BenchReturns(result1 solver.BenchResult) {
+	fake.benchMutex.Lock()
+	defer fake.benchMutex.Unlock()
+	fake.BenchStub = nil
+	fake.benchReturns = struct {
+		result1 solver.BenchResult
+	}{result1}
+}
+
+func (fake *FakeSolver) BenchReturnsOnCall(i int, result1 solver.BenchResult) {
+	fake.benchMutex.Lock()
+	defer fake.benchMutex.Unlock()
+	fake.BenchStub = nil
+	if fake.benchReturnsOnCall == nil {
+		fake.benchReturnsOnCall = make(map[int]struct {
+			result1 solver.BenchResult
+		})
+	}
+	fake.benchReturnsOnCall[i] = struct {
+		result1 solver.BenchResult
+	}{result1}
+}
+
 func (fake *FakeSolver) DoMove(arg1 string) {
 	fake.doMoveMutex.Lock()
 	fake.doMoveArgsForCall = append(fake.doMoveArgsForCall, struct {
@@ -106,6 +204,58 @@ func (fake *FakeSolver) DoMoveArgsForCall(i int) string {
 	return argsForCall.arg1
 }
 
+func (fake *FakeSolver) Eval() utils.Evaluation {
+	fake.evalMutex.Lock()
+	ret, specificReturn := fake.evalReturnsOnCall[len(fake.evalArgsForCall)]
+	fake.evalArgsForCall = append(fake.evalArgsForCall, struct {
+	}{})
+	fake.recordInvocation("Eval", []interface{}{})
+	fake.evalMutex.Unlock()
+	if fake.EvalStub != nil {
+		return fake.EvalStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.evalReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeSolver) EvalCallCount() int {
+	fake.evalMutex.RLock()
+	defer fake.evalMutex.RUnlock()
+	return len(fake.evalArgsForCall)
+}
+
+func (fake *FakeSolver) EvalCalls(stub func() utils.Evaluation) {
+	fake.evalMutex.Lock()
+	defer fake.evalMutex.Unlock()
+	fake.EvalStub = stub
+}
+
+func (fake *FakeSolver) EvalReturns(result1 utils.Evaluation) {
+	fake.evalMutex.Lock()
+	defer fake.evalMutex.Unlock()
+	fake.EvalStub = nil
+	fake.evalReturns = struct {
+		result1 utils.Evaluation
+	}{result1}
+}
+
+func (fake *FakeSolver) EvalReturnsOnCall(i int, result1 utils.Evaluation) {
+	fake.evalMutex.Lock()
+	defer fake.evalMutex.Unlock()
+	fake.EvalStub = nil
+	if fake.evalReturnsOnCall == nil {
+		fake.evalReturnsOnCall = make(map[int]struct {
+			result1 utils.Evaluation
+		})
+	}
+	fake.evalReturnsOnCall[i] = struct {
+		result1 utils.Evaluation
+	}{result1}
+}
+
 func (fake *FakeSolver) GetOption(arg1 string) *string {
 	fake.getOptionMutex.Lock()
 	ret, specificReturn := fake.getOptionReturnsOnCall[len(fake.getOptionArgsForCall)]
@@ -218,6 +368,89 @@ func (fake *FakeSolver) GetOptionsReturnsOnCall(i int, result1 []*solver.Option)
 	}{result1}
 }
 
+func (fake *FakeSolver) NewGame() {
+	fake.newGameMutex.Lock()
+	fake.newGameArgsForCall = append(fake.newGameArgsForCall, struct {
+	}{})
+	fake.recordInvocation("NewGame", []interface{}{})
+	fake.newGameMutex.Unlock()
+	if fake.NewGameStub != nil {
+		fake.NewGameStub()
+	}
+}
+
+func (fake *FakeSolver) NewGameCallCount() int {
+	fake.newGameMutex.RLock()
+	defer fake.newGameMutex.RUnlock()
+	return len(fake.newGameArgsForCall)
+}
+
+func (fake *FakeSolver) NewGameCalls(stub func()) {
+	fake.newGameMutex.Lock()
+	defer fake.newGameMutex.Unlock()
+	fake.NewGameStub = stub
+}
+
+func (fake *FakeSolver) Perft(arg1 int) []board.PerftResult {
+	fake.perftMutex.Lock()
+	ret, specificReturn := fake.perftReturnsOnCall[len(fake.perftArgsForCall)]
+	fake.perftArgsForCall = append(fake.perftArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("Perft", []interface{}{arg1})
+	fake.perftMutex.Unlock()
+	if fake.PerftStub != nil {
+		return fake.PerftStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.perftReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeSolver) PerftCallCount() int {
+	fake.perftMutex.RLock()
+	defer fake.perftMutex.RUnlock()
+	return len(fake.perftArgsForCall)
+}
+
+func (fake *FakeSolver) PerftCalls(stub func(int) []board.PerftResult) {
+	fake.perftMutex.Lock()
+	defer fake.perftMutex.Unlock()
+	fake.PerftStub = stub
+}
+
+func (fake *FakeSolver) PerftArgsForCall(i int) int {
+	fake.perftMutex.RLock()
+	defer fake.perftMutex.RUnlock()
+	argsForCall := fake.perftArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSolver) PerftReturns(result1 []board.PerftResult) {
+	fake.perftMutex.Lock()
+	defer fake.perftMutex.Unlock()
+	fake.PerftStub = nil
+	fake.perftReturns = struct {
+		result1 []board.PerftResult
+	}{result1}
+}
+
+func (fake *FakeSolver) PerftReturnsOnCall(i int, result1 []board.PerftResult) {
+	fake.perftMutex.Lock()
+	defer fake.perftMutex.Unlock()
+	fake.PerftStub = nil
+	if fake.perftReturnsOnCall == nil {
+		fake.perftReturnsOnCall = make(map[int]struct {
+			result1 []board.PerftResult
+		})
+	}
+	fake.perftReturnsOnCall[i] = struct {
+		result1 []board.PerftResult
+	}{result1}
+}
+
 func (fake *FakeSolver) PonderHit() {
 	fake.ponderHitMutex.Lock()
 	fake.ponderHitArgsForCall = append(fake.ponderHitArgsForCall, struct {
@@ -423,12 +656,20 @@ func (fake *FakeSolver) StopSearchCalls(stub func()) {
 func (fake *FakeSolver) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.benchMutex.RLock()
+	defer fake.benchMutex.RUnlock()
 	fake.doMoveMutex.RLock()
 	defer fake.doMoveMutex.RUnlock()
+	fake.evalMutex.RLock()
+	defer fake.evalMutex.RUnlock()
 	fake.getOptionMutex.RLock()
 	defer fake.getOptionMutex.RUnlock()
 	fake.getOptionsMutex.RLock()
 	defer fake.getOptionsMutex.RUnlock()
+	fake.newGameMutex.RLock()
+	defer fake.newGameMutex.RUnlock()
+	fake.perftMutex.RLock()
+	defer fake.perftMutex.RUnlock()
 	fake.ponderHitMutex.RLock()
 	defer fake.ponderHitMutex.RUnlock()
 	fake.setOptionMutex.RLock()
@@ -0,0 +1,186 @@
+package solver
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/notnil/chess"
+)
+
+// MoveOverheadOptionName is the name of the Option that configures the time
+// reserved for communication with the GUI.
+const MoveOverheadOptionName = "Move Overhead"
+
+const (
+	defaultMovesToGo = 30                    // assumed moves to go in sudden death games
+	minimumBudget    = time.Millisecond      // smallest time budget handed out
+	hardLimitFactor  = 4                     // hard limit as a multiple of the soft limit
+	maxClockFraction = 2                     // never use more than 1/x of the clock on one move
+	defaultOverhead  = 10 * time.Millisecond // default Move Overhead
+)
+
+// NewMoveOverheadOption returns the Option that configures the Move Overhead,
+// in ms, of a TimeManager.
+func NewMoveOverheadOption() *Option {
+	return &Option{
+		Name:    MoveOverheadOptionName,
+		Type:    OptionSpinType,
+		Default: strconv.Itoa(int(defaultOverhead / time.Millisecond)),
+		Min:     "0",
+		Max:     "5000"}
+}
+
+// NewTimeManager returns a TimeManager for a search of the current position
+// with the given SearchParams, reserving the Move Overhead set as an option.
+func (solver *AbstractSolver) NewTimeManager(sp *SearchParams) *TimeManager {
+	overhead := defaultOverhead
+	if opt := solver.GetOption(MoveOverheadOptionName); opt != nil {
+		if ms, err := strconv.Atoi(*opt); err == nil {
+			overhead = millis(ms)
+		}
+	}
+	return NewTimeManager(sp, solver.Game.Position().Turn(), overhead)
+}
+
+// TimeManager computes time budgets for a search from the SearchParams of the
+// "go" command.  The soft limit is the time after which a search should not
+// start another iteration, and the hard limit is the time after which the
+// search must stop.  The clock starts when the TimeManager is created, or, when
+// pondering, on Start, which should be called on "ponderhit".
+type TimeManager struct {
+	soft    time.Duration
+	hard    time.Duration
+	limited bool
+
+	mutex   sync.RWMutex
+	start   time.Time
+	started bool
+}
+
+// NewTimeManager returns a TimeManager for a search with the given
+// SearchParams, with turn to move, reserving overhead on each move for
+// communication with the GUI.
+func NewTimeManager(sp *SearchParams, turn chess.Color, overhead time.Duration) *TimeManager {
+	tm := &TimeManager{}
+
+	if !sp.Infinite {
+		if sp.Movetime > 0 {
+			tm.limited = true
+			movetime := millis(sp.Movetime)
+			tm.soft = clampBudget(movetime-overhead, movetime)
+			tm.hard = tm.soft
+		} else if remaining, inc := clock(sp, turn); remaining > 0 {
+			tm.limited = true
+			tm.soft, tm.hard = budget(remaining, inc, sp.Movestogo, overhead)
+		}
+	}
+
+	if !sp.Ponder {
+		tm.Start()
+	}
+
+	return tm
+}
+
+func clock(sp *SearchParams, turn chess.Color) (remaining, inc time.Duration) {
+	if turn == chess.White {
+		return millis(sp.Wtime), millis(sp.Winc)
+	}
+	return millis(sp.Btime), millis(sp.Binc)
+}
+
+func budget(remaining, inc time.Duration, movestogo int,
+	overhead time.Duration) (soft, hard time.Duration) {
+
+	if movestogo <= 0 || movestogo > defaultMovesToGo {
+		movestogo = defaultMovesToGo
+	}
+
+	available := clampBudget(remaining-overhead, remaining)
+
+	soft = available/time.Duration(movestogo) + inc*3/4
+	hard = soft * hardLimitFactor
+
+	// with one move to go we can use (almost) the whole clock
+	limit := available / maxClockFraction
+	if movestogo == 1 {
+		limit = available
+	}
+
+	return clampBudget(soft, limit), clampBudget(hard, limit)
+}
+
+func clampBudget(d, max time.Duration) time.Duration {
+	if d > max {
+		d = max
+	}
+	if d < minimumBudget {
+		d = minimumBudget
+	}
+	return d
+}
+
+func millis(ms int) time.Duration {
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Start starts the clock.  Calling Start on a started TimeManager does nothing.
+func (tm *TimeManager) Start() {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	if !tm.started {
+		tm.start = time.Now()
+		tm.started = true
+	}
+}
+
+// Started returns true if the clock is running.
+func (tm *TimeManager) Started() bool {
+	tm.mutex.RLock()
+	defer tm.mutex.RUnlock()
+
+	return tm.started
+}
+
+// Limited returns true if the search is limited by time at all.
+func (tm *TimeManager) Limited() bool {
+	return tm.limited
+}
+
+// SoftLimit returns the time after which no new iteration should be started.
+func (tm *TimeManager) SoftLimit() time.Duration {
+	return tm.soft
+}
+
+// HardLimit returns the time after which the search must be stopped.
+func (tm *TimeManager) HardLimit() time.Duration {
+	return tm.hard
+}
+
+// Elapsed returns the time since the clock started.
+func (tm *TimeManager) Elapsed() time.Duration {
+	tm.mutex.RLock()
+	defer tm.mutex.RUnlock()
+
+	if !tm.started {
+		return 0
+	}
+	return time.Since(tm.start)
+}
+
+// SoftExpired returns true if the search is limited, the clock is running and
+// the soft limit has passed.
+func (tm *TimeManager) SoftExpired() bool {
+	return tm.limited && tm.Started() && tm.Elapsed() >= tm.soft
+}
+
+// HardExpired returns true if the search is limited, the clock is running and
+// the hard limit has passed.
+func (tm *TimeManager) HardExpired() bool {
+	return tm.limited && tm.Started() && tm.Elapsed() >= tm.hard
+}
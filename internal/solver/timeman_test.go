@@ -0,0 +1,123 @@
+package solver_test
+
+import (
+	"time"
+
+	"github.com/notnil/chess"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/mhv2109/uci-impl/internal/solver"
+)
+
+var _ = Describe("TimeManager", func() {
+	var sp *SearchParams
+
+	BeforeEach(func() {
+		sp = NewSearchParams()
+	})
+
+	It("Isn't limited without clock values", func() {
+		tm := NewTimeManager(sp, chess.White, 0)
+
+		Expect(tm.Limited()).
+			To(BeFalse())
+		Expect(tm.HardExpired()).
+			To(BeFalse())
+	})
+
+	It("Isn't limited in infinite mode", func() {
+		sp.Infinite = true
+		sp.Wtime = 1000
+
+		tm := NewTimeManager(sp, chess.White, 0)
+		Expect(tm.Limited()).
+			To(BeFalse())
+	})
+
+	It("Uses movetime less overhead", func() {
+		sp.Movetime = 1000
+
+		tm := NewTimeManager(sp, chess.White, 50*time.Millisecond)
+		Expect(tm.SoftLimit()).
+			To(Equal(950 * time.Millisecond))
+		Expect(tm.HardLimit()).
+			To(Equal(950 * time.Millisecond))
+	})
+
+	It("Uses the clock of the side to move", func() {
+		sp.Wtime = 300000
+		sp.Btime = 3000
+
+		white := NewTimeManager(sp, chess.White, 0)
+		black := NewTimeManager(sp, chess.Black, 0)
+		Expect(white.SoftLimit()).
+			To(BeNumerically(">", black.SoftLimit()))
+	})
+
+	It("Keeps the hard limit within the clock", func() {
+		sp.Wtime = 300000
+
+		tm := NewTimeManager(sp, chess.White, 0)
+		Expect(tm.SoftLimit()).
+			To(Equal(10 * time.Second))
+		Expect(tm.HardLimit()).
+			To(Equal(40 * time.Second))
+	})
+
+	It("Spends more time with increments", func() {
+		sp.Wtime = 300000
+		without := NewTimeManager(sp, chess.White, 0)
+
+		sp.Winc = 2000
+		with := NewTimeManager(sp, chess.White, 0)
+		Expect(with.SoftLimit()).
+			To(Equal(without.SoftLimit() + 1500*time.Millisecond))
+	})
+
+	It("Divides the clock by movestogo", func() {
+		sp.Wtime = 60000
+		sp.Movestogo = 10
+
+		tm := NewTimeManager(sp, chess.White, 0)
+		Expect(tm.SoftLimit()).
+			To(Equal(6 * time.Second))
+	})
+
+	It("Can use the whole clock on the last move before the time control", func() {
+		sp.Wtime = 1000
+		sp.Movestogo = 1
+
+		tm := NewTimeManager(sp, chess.White, 100*time.Millisecond)
+		Expect(tm.HardLimit()).
+			To(Equal(900 * time.Millisecond))
+	})
+
+	It("Doesn't start the clock until ponderhit", func() {
+		sp.Ponder = true
+		sp.Movetime = 1
+
+		tm := NewTimeManager(sp, chess.White, 0)
+		time.Sleep(2 * time.Millisecond)
+		Expect(tm.Started()).
+			To(BeFalse())
+		Expect(tm.HardExpired()).
+			To(BeFalse())
+
+		tm.Start()
+		Eventually(tm.HardExpired).
+			Should(BeTrue())
+	})
+
+	It("Reserves the Move Overhead option of a solver", func() {
+		sp.Movetime = 1000
+
+		base := NewAbstractSolver(NewOptions())
+		Expect(base.NewTimeManager(sp).SoftLimit()).
+			To(Equal(990 * time.Millisecond))
+
+		base.SetOption(MoveOverheadOptionName, "100")
+		Expect(base.NewTimeManager(sp).SoftLimit()).
+			To(Equal(900 * time.Millisecond))
+	})
+})
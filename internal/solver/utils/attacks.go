@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+const (
+	fileH board.Bitboard = fileA << 7
+)
+
+// pieceAttacks returns the squares a piece of type t on sq attacks, except
+// pawns, given the occupied squares.
+func pieceAttacks(t board.PieceType, sq board.Square, occupied board.Bitboard) board.Bitboard {
+	switch t {
+	case board.Knight:
+		return board.KnightAttacks(sq)
+	case board.Bishop:
+		return board.BishopAttacks(sq, occupied)
+	case board.Rook:
+		return board.RookAttacks(sq, occupied)
+	case board.Queen:
+		return board.QueenAttacks(sq, occupied)
+	case board.King:
+		return board.KingAttacks(sq)
+	}
+	return 0
+}
+
+// pawnAttackMap returns the squares attacked by the pawns of color us.
+func pawnAttackMap(us board.Color, pawns board.Bitboard) board.Bitboard {
+	if us == board.Black {
+		return (pawns>>9)&^fileH | (pawns>>7)&^fileA
+	}
+	return (pawns<<7)&^fileH | (pawns<<9)&^fileA
+}
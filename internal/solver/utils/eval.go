@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+// Score is an evaluation in the middlegame and in the endgame, which are
+// interpolated by the game phase.
+type Score struct {
+	MG, EG CentiPawns
+}
+
+// Add returns the sum of s and o.
+func (s Score) Add(o Score) Score {
+	return Score{s.MG + o.MG, s.EG + o.EG}
+}
+
+// Sub returns the difference of s and o.
+func (s Score) Sub(o Score) Score {
+	return Score{s.MG - o.MG, s.EG - o.EG}
+}
+
+// Term is a part of the evaluation.
+type Term int
+
+const (
+	MaterialTerm Term = iota
+	PositionTerm      // piece-square tables
+	PawnsTerm         // pawn structure
+	KingSafetyTerm
+	MobilityTerm
+	NetworkTerm // output of a neural network
+	nTerms
+)
+
+// Terms lists all evaluation terms, in the order they're reported.
+var Terms = []Term{MaterialTerm, PositionTerm, PawnsTerm, KingSafetyTerm, MobilityTerm, NetworkTerm}
+
+func (t Term) String() string {
+	switch t {
+	case MaterialTerm:
+		return "Material"
+	case PositionTerm:
+		return "Position"
+	case PawnsTerm:
+		return "Pawns"
+	case KingSafetyTerm:
+		return "King safety"
+	case MobilityTerm:
+		return "Mobility"
+	case NetworkTerm:
+		return "Network"
+	}
+	return "Unknown"
+}
+
+// Phase weights of the pieces.  The phase runs from MaxPhase with all pieces on
+// the board down to 0 in a pawn endgame.
+const (
+	knightPhase = 1
+	bishopPhase = 1
+	rookPhase   = 2
+	queenPhase  = 4
+	MaxPhase    = 4*knightPhase + 4*bishopPhase + 4*rookPhase + 2*queenPhase
+)
+
+// Evaluation is the static evaluation of a board, broken down by term and
+// color.
+type Evaluation struct {
+	Phase int
+	terms [nTerms][2]Score
+	used  [nTerms]bool // terms the evaluator added, to report
+}
+
+// Term returns the score of term for color.
+func (e *Evaluation) Term(term Term, color board.Color) Score {
+	return e.terms[term][color]
+}
+
+// Add adds s to the score of term for color.
+func (e *Evaluation) Add(term Term, color board.Color, s Score) {
+	e.add(term, color, s)
+}
+
+func (e *Evaluation) add(term Term, color board.Color, s Score) {
+	e.used[term] = true
+	e.terms[term][color] = e.terms[term][color].Add(s)
+}
+
+// Taper interpolates s between the middlegame and the endgame by the phase.
+func (e *Evaluation) Taper(s Score) CentiPawns {
+	return (s.MG*CentiPawns(e.Phase) + s.EG*CentiPawns(MaxPhase-e.Phase)) / MaxPhase
+}
+
+// Total returns the evaluation from White's point of view.
+func (e *Evaluation) Total() CentiPawns {
+	var total Score
+	for _, term := range e.terms {
+		total = total.Add(term[0]).Sub(term[1])
+	}
+	return e.Taper(total)
+}
+
+// String formats the evaluation as a table of the terms the evaluator used for
+// each side, in pawns, followed by the total.
+func (e *Evaluation) String() string {
+	var b strings.Builder
+	line := "-------------+-------------+-------------+-------------\n"
+
+	b.WriteString("        Term |    White    |    Black    |    Total\n")
+	b.WriteString("             |   MG    EG  |   MG    EG  |   MG    EG\n")
+	b.WriteString(line)
+	var total Score
+	for _, term := range Terms {
+		if !e.used[term] {
+			continue
+		}
+		white, black := e.Term(term, board.White), e.Term(term, board.Black)
+		diff := white.Sub(black)
+		total = total.Add(diff)
+		fmt.Fprintf(&b, "%12s | %s | %s | %s\n", term, formatScore(white), formatScore(black), formatScore(diff))
+	}
+	b.WriteString(line)
+	fmt.Fprintf(&b, "%12s | %11s | %11s | %s\n", "Total", "", "", formatScore(total))
+	fmt.Fprintf(&b, "\nPhase: %d/%d\n", e.Phase, MaxPhase)
+	fmt.Fprintf(&b, "Evaluation: %+.2f (white side)", pawns(e.Total()))
+
+	return b.String()
+}
+
+func formatScore(s Score) string {
+	return fmt.Sprintf("%5.2f %5.2f", pawns(s.MG), pawns(s.EG))
+}
+
+func pawns(cp CentiPawns) float64 {
+	return float64(cp) / 100
+}
+
+// Evaluator statically evaluates boards by material, piece placement, which
+// shifts to endgame piece placement as pieces are traded, pawn structure, king
+// safety and mobility.  An Evaluator caches pawn structures, so it must not be
+// shared between goroutines.
+type Evaluator struct {
+	pawns   *pawnTable
+	version uint32 // of the parameters the pawn table was filled with
+}
+
+func NewEvaluator() *Evaluator {
+	return &Evaluator{newPawnTable(), atomic.LoadUint32(&paramsVersion)}
+}
+
+// Evaluate returns the evaluation of position from White's point of view.
+func (evaluator *Evaluator) Evaluate(position *board.Position) CentiPawns {
+	e := evaluator.Trace(position)
+	return e.Total()
+}
+
+// Trace returns the evaluation of position broken down by term.
+func (evaluator *Evaluator) Trace(position *board.Position) Evaluation {
+	e := traceMaterial(position, true)
+
+	if version := atomic.LoadUint32(&paramsVersion); version != evaluator.version {
+		evaluator.pawns.Clear()
+		evaluator.version = version
+	}
+	pawns := [2]board.Bitboard{position.Pieces(board.White, board.Pawn), position.Pieces(board.Black, board.Pawn)}
+	entry := evaluator.pawns.Probe(position.PawnKey(), pawns)
+	for _, us := range []board.Color{board.White, board.Black} {
+		e.add(PawnsTerm, us, entry.score[us].Add(passedPawnKings(position, us, entry.passed[us])))
+		e.add(KingSafetyTerm, us, kingSafety(position, us))
+		e.add(MobilityTerm, us, mobility(position, us))
+	}
+
+	return e
+}
+
+// traceMaterial returns the evaluation of the material of position and the
+// game phase, and of piece placement if squares is true.
+func traceMaterial(position *board.Position, squares bool) Evaluation {
+	var e Evaluation
+	for occupied := position.AllOccupied(); occupied != 0; {
+		sq := occupied.Pop()
+		piece := position.Piece(sq)
+		if piece.Type() != board.King {
+			value := pieceValue(piece.Type())
+			e.add(MaterialTerm, piece.Color(), Score{value, value})
+		}
+		if squares {
+			e.add(PositionTerm, piece.Color(), pieceSquare(piece, sq))
+		}
+		e.Phase += piecePhase(piece.Type())
+	}
+	if e.Phase > MaxPhase {
+		// early promotions
+		e.Phase = MaxPhase
+	}
+	return e
+}
+
+// MaterialEvaluator evaluates boards by material only.
+type MaterialEvaluator struct{}
+
+// Evaluate returns the material balance of position from White's point of
+// view.
+func (MaterialEvaluator) Evaluate(position *board.Position) CentiPawns {
+	var total CentiPawns
+	for occupied := position.AllOccupied(); occupied != 0; {
+		piece := position.Piece(occupied.Pop())
+		if piece.Color() == board.White {
+			total += pieceValue(piece.Type())
+		} else {
+			total -= pieceValue(piece.Type())
+		}
+	}
+	return total
+}
+
+// Trace returns the evaluation of position, which only has a material term.
+func (MaterialEvaluator) Trace(position *board.Position) Evaluation {
+	return traceMaterial(position, false)
+}
+
+func piecePhase(t board.PieceType) int {
+	switch t {
+	case board.Knight:
+		return knightPhase
+	case board.Bishop:
+		return bishopPhase
+	case board.Rook:
+		return rookPhase
+	case board.Queen:
+		return queenPhase
+	}
+	return 0
+}
@@ -0,0 +1,217 @@
+package utils_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	. "github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+var _ = Describe("Evaluator", func() {
+	var evaluator *Evaluator
+
+	BeforeEach(func() {
+		evaluator = NewEvaluator()
+	})
+
+	position := func(fen string) *board.Position {
+		pos, err := board.ParseFEN(fen)
+		Expect(err).
+			ToNot(HaveOccurred())
+		return pos
+	}
+
+	It("Evaluates the start position as equal", func() {
+		Expect(evaluator.Evaluate(board.NewPosition())).
+			To(BeZero())
+	})
+
+	It("Evaluates mirrored positions with opposite signs", func() {
+		white := position("r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4")
+		black := position("rnbqk2r/pppp1ppp/5n2/2b1p3/4P3/2N2N2/PPPP1PPP/R1BQKB1R b KQkq - 4 4")
+		Expect(evaluator.Evaluate(white)).
+			To(Equal(-evaluator.Evaluate(black)))
+	})
+
+	It("Includes material", func() {
+		e := evaluator.Trace(position("4k3/8/8/8/8/8/8/3QK3 w - - 0 1"))
+		Expect(e.Term(MaterialTerm, board.White)).
+			To(Equal(Score{QueenValue, QueenValue}))
+		Expect(e.Term(MaterialTerm, board.Black)).
+			To(Equal(Score{}))
+	})
+
+	It("Prefers knights in the center", func() {
+		center := position("4k3/8/8/8/3N4/8/8/4K3 w - - 0 1")
+		rim := position("4k3/8/8/8/N7/8/8/4K3 w - - 0 1")
+		Expect(evaluator.Evaluate(center)).
+			To(BeNumerically(">", evaluator.Evaluate(rim)))
+	})
+
+	It("Tracks the game phase", func() {
+		Expect(evaluator.Trace(board.NewPosition()).Phase).
+			To(Equal(MaxPhase))
+		Expect(evaluator.Trace(position("4k3/4p3/8/8/8/8/4P3/4K3 w - - 0 1")).Phase).
+			To(BeZero())
+	})
+
+	It("Centralizes the king in the endgame only", func() {
+		endgame := func(king string) CentiPawns {
+			return evaluator.Evaluate(position("4k3/8/8/8/" + king + "/8/8/8 w - - 0 1"))
+		}
+		Expect(endgame("3K4")).
+			To(BeNumerically(">", endgame("7K")))
+
+		middlegame := func(rank1 string) CentiPawns {
+			return evaluator.Evaluate(position("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/" + rank1 + " w - - 0 1"))
+		}
+		Expect(middlegame("RNBQ1RK1")).
+			To(BeNumerically(">", middlegame("RNBQKR2")))
+	})
+})
+
+var _ = Describe("Pawn structure", func() {
+	var evaluator *Evaluator
+
+	BeforeEach(func() {
+		evaluator = NewEvaluator()
+	})
+
+	pawns := func(s string, color board.Color) Score {
+		pos, err := board.ParseFEN(s)
+		Expect(err).
+			ToNot(HaveOccurred())
+		e := evaluator.Trace(pos)
+		return e.Term(PawnsTerm, color)
+	}
+
+	It("Is even in the start position", func() {
+		e := evaluator.Trace(board.NewPosition())
+		Expect(e.Term(PawnsTerm, board.White)).
+			To(Equal(Score{}))
+		Expect(e.Term(PawnsTerm, board.Black)).
+			To(Equal(Score{}))
+	})
+
+	It("Penalizes doubled and isolated pawns", func() {
+		healthy := pawns("4k3/pppppppp/8/8/8/8/PPP2PPP/4K3 w - - 0 1", board.White)
+		doubled := pawns("4k3/pppppppp/8/8/8/2P5/PPP2PPP/4K3 w - - 0 1", board.White)
+		isolated := pawns("4k3/pppppppp/8/8/8/8/PP1P1PPP/4K3 w - - 0 1", board.White)
+		Expect(doubled.EG).
+			To(BeNumerically("<", healthy.EG))
+		Expect(isolated.EG).
+			To(BeNumerically("<", healthy.EG))
+	})
+
+	It("Penalizes backward pawns", func() {
+		backward := pawns("4k3/8/2p5/8/3P4/8/4P3/4K3 w - - 0 1", board.White)
+		supported := pawns("4k3/8/2p5/8/3P4/4P3/8/4K3 w - - 0 1", board.White)
+		Expect(backward.MG).
+			To(BeNumerically("<", supported.MG))
+	})
+
+	It("Rewards passed pawns by rank", func() {
+		far := pawns("4k3/8/8/8/8/P7/8/4K3 w - - 0 1", board.White)
+		near := pawns("4k3/8/P7/8/8/8/8/4K3 w - - 0 1", board.White)
+		blocked := pawns("4k3/1p6/P7/8/8/8/8/4K3 w - - 0 1", board.White)
+		Expect(near.EG).
+			To(BeNumerically(">", far.EG))
+		Expect(blocked.EG).
+			To(BeNumerically("<", near.EG))
+	})
+
+	It("Rewards passed pawns the enemy king can't reach", func() {
+		escorted := pawns("7k/8/1P6/1K6/8/8/8/8 w - - 0 1", board.White)
+		caught := pawns("8/1k6/1P6/8/8/8/8/6K1 w - - 0 1", board.White)
+		Expect(escorted.EG).
+			To(BeNumerically(">", caught.EG))
+	})
+
+	It("Doesn't mistake pawns for an empty board", func() {
+		Expect(pawns("4k3/8/8/8/8/8/8/4K3 w - - 0 1", board.White)).
+			To(Equal(Score{}))
+		// a key symmetric in the colors hashes these pawns to zero too
+		Expect(pawns("4k3/8/p7/8/8/8/P7/4K3 w - - 0 1", board.White)).
+			ToNot(Equal(Score{}))
+	})
+
+	It("Evaluates cached pawn structures the same", func() {
+		fen := "4k3/pp3ppp/8/2pP4/8/8/PP3PPP/4K3 w - - 0 1"
+		Expect(pawns(fen, board.White)).
+			To(Equal(pawns(fen, board.White)))
+		Expect(pawns(fen, board.Black)).
+			To(Equal(pawns(fen, board.Black)))
+	})
+})
+
+var _ = Describe("King safety and mobility", func() {
+	var evaluator *Evaluator
+
+	BeforeEach(func() {
+		evaluator = NewEvaluator()
+	})
+
+	term := func(s string, term Term, color board.Color) Score {
+		pos, err := board.ParseFEN(s)
+		Expect(err).
+			ToNot(HaveOccurred())
+		e := evaluator.Trace(pos)
+		return e.Term(term, color)
+	}
+
+	It("Rewards a pawn shelter", func() {
+		sheltered := term("6k1/8/8/8/8/8/5PPP/6K1 w - - 0 1", KingSafetyTerm, board.White)
+		exposed := term("6k1/8/8/8/5PPP/8/8/6K1 w - - 0 1", KingSafetyTerm, board.White)
+		Expect(sheltered.MG).
+			To(BeNumerically(">", exposed.MG))
+	})
+
+	It("Penalizes open files next to the king", func() {
+		closed := term("6k1/6pp/8/8/8/8/5PPP/6K1 w - - 0 1", KingSafetyTerm, board.White)
+		open := term("6k1/7p/8/8/8/8/5P1P/6K1 w - - 0 1", KingSafetyTerm, board.White)
+		Expect(closed.MG).
+			To(BeNumerically(">", open.MG))
+	})
+
+	It("Penalizes several pieces attacking the king zone", func() {
+		safe := term("6k1/8/8/8/8/8/5PPP/qr4K1 w - - 0 1", KingSafetyTerm, board.White)
+		attacked := term("6k1/8/8/8/8/5n2/5PPP/q5K1 w - - 0 1", KingSafetyTerm, board.White)
+		Expect(attacked.MG).
+			To(BeNumerically("<", safe.MG))
+	})
+
+	It("Rewards pieces that control more squares", func() {
+		active := term("6k1/8/8/8/3B4/8/8/6K1 w - - 0 1", MobilityTerm, board.White)
+		blocked := term("6k1/8/8/8/8/8/1P6/B5K1 w - - 0 1", MobilityTerm, board.White)
+		Expect(active.MG).
+			To(BeNumerically(">", blocked.MG))
+	})
+
+	It("Doesn't count squares attacked by enemy pawns", func() {
+		free := term("6k1/8/8/8/8/8/8/N5K1 w - - 0 1", MobilityTerm, board.White)
+		covered := term("6k1/8/8/8/8/1p6/8/N5K1 w - - 0 1", MobilityTerm, board.White)
+		Expect(covered.MG).
+			To(BeNumerically("<", free.MG))
+	})
+})
+
+var _ = Describe("Evaluation", func() {
+	It("Formats each term for each side", func() {
+		e := NewEvaluator().Trace(board.NewPosition())
+		lines := strings.Split(e.String(), "\n")
+		for _, term := range []Term{MaterialTerm, PositionTerm, PawnsTerm, KingSafetyTerm, MobilityTerm} {
+			Expect(lines).
+				To(ContainElement(HavePrefix(fmt.Sprintf("%12s |", term))))
+		}
+		Expect(lines).
+			ToNot(ContainElement(HavePrefix(fmt.Sprintf("%12s |", NetworkTerm))))
+		Expect(lines).
+			To(ContainElement(MatchRegexp(`^\s+Material \| 39\.00 39\.00 \| 39\.00 39\.00 \|  0\.00  0\.00$`)))
+		Expect(lines[len(lines)-1]).
+			To(Equal("Evaluation: +0.00 (white side)"))
+	})
+})
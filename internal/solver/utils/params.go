@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Param is a tunable evaluation weight.  Parameters are global, so they must
+// only be changed between searches.
+type Param struct {
+	Name     string
+	Default  int
+	Min, Max int
+
+	value *CentiPawns
+}
+
+// Value returns the current value of the parameter.
+func (param *Param) Value() int {
+	return int(*param.value)
+}
+
+// Set changes the parameter to value, clamped to its range.
+func (param *Param) Set(value int) {
+	if value < param.Min {
+		value = param.Min
+	} else if value > param.Max {
+		value = param.Max
+	}
+	if CentiPawns(value) != *param.value {
+		*param.value = CentiPawns(value)
+		atomic.AddUint32(&paramsVersion, 1)
+	}
+}
+
+var (
+	params        []*Param
+	paramsVersion uint32 // changes whenever a parameter does, to invalidate caches
+)
+
+// Params returns all evaluation parameters, in a fixed order.
+func Params() []*Param {
+	return params
+}
+
+// GetParam returns the parameter named name, ignoring case, or nil.
+func GetParam(name string) *Param {
+	for _, param := range params {
+		if strings.EqualFold(param.Name, name) {
+			return param
+		}
+	}
+	return nil
+}
+
+// ResetParams sets all parameters to their defaults.
+func ResetParams() {
+	for _, param := range params {
+		param.Set(param.Default)
+	}
+}
+
+// WriteParams writes the current parameter values to w, one "name = value"
+// line each, as read by LoadParams.
+func WriteParams(w io.Writer) error {
+	for _, param := range params {
+		if _, err := fmt.Fprintf(w, "%s = %d\n", param.Name, param.Value()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadParams reads parameter values written by WriteParams from r, and makes
+// them the defaults.  Blank lines and lines starting with "#" are ignored, and
+// parameters not in r are left unchanged.
+func LoadParams(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(text, "=", 2)
+		if len(fields) != 2 {
+			return fmt.Errorf("line %d: expected \"name = value\": %q", line, text)
+		}
+		param := GetParam(strings.TrimSpace(fields[0]))
+		if param == nil {
+			return fmt.Errorf("line %d: unknown parameter %q", line, strings.TrimSpace(fields[0]))
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+
+		param.Set(value)
+		param.Default = param.Value()
+	}
+	return scanner.Err()
+}
+
+func register(name string, value *CentiPawns, min, max int) {
+	params = append(params, &Param{name, int(*value), min, max, value})
+}
+
+// registerScore registers the middlegame and endgame parts of s.
+func registerScore(name string, s *Score, min, max int) {
+	register(name+" MG", &s.MG, min, max)
+	register(name+" EG", &s.EG, min, max)
+}
+
+func init() {
+	register("Pawn Value", &PawnValue, 0, 2000)
+	register("Knight Value", &KnightValue, 0, 2000)
+	register("Bishop Value", &BishopValue, 0, 2000)
+	register("Rook Value", &RookValue, 0, 2000)
+	register("Queen Value", &QueenValue, 0, 4000)
+
+	registerScore("Pawn Squares", &pawnSquares, 0, 400)
+	registerScore("Knight Squares", &knightSquares, 0, 400)
+	registerScore("Bishop Squares", &bishopSquares, 0, 400)
+	registerScore("Rook Squares", &rookSquares, 0, 400)
+	registerScore("Queen Squares", &queenSquares, 0, 400)
+	registerScore("King Squares", &kingSquares, 0, 400)
+
+	registerScore("Doubled Pawn", &doubledPawn, -500, 500)
+	registerScore("Isolated Pawn", &isolatedPawn, -500, 500)
+	registerScore("Backward Pawn", &backwardPawn, -500, 500)
+	for rank := 1; rank < 7; rank++ {
+		name := fmt.Sprintf("Passed Pawn Rank %d", rank+1)
+		register(name+" MG", &passedPawnMG[rank], -500, 500)
+		register(name+" EG", &passedPawnEG[rank], -500, 500)
+	}
+	register("Passed Pawn Enemy King", &passedPawnEnemyKing, -100, 100)
+	register("Passed Pawn Own King", &passedPawnOwnKing, -100, 100)
+
+	registerScore("King Shelter Pawn", &kingShelterPawn, -500, 500)
+	registerScore("King Semi-Open File", &kingSemiOpenFile, -500, 500)
+	registerScore("King Open File", &kingOpenFile, -500, 500)
+	registerScore("King Attack Knight", &kingAttackKnight, -500, 500)
+	registerScore("King Attack Bishop", &kingAttackBishop, -500, 500)
+	registerScore("King Attack Rook", &kingAttackRook, -500, 500)
+	registerScore("King Attack Queen", &kingAttackQueen, -500, 500)
+
+	registerScore("Knight Mobility", &knightMobility, -100, 100)
+	registerScore("Bishop Mobility", &bishopMobility, -100, 100)
+	registerScore("Rook Mobility", &rookMobility, -100, 100)
+	registerScore("Queen Mobility", &queenMobility, -100, 100)
+}
@@ -0,0 +1,116 @@
+package utils_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/mhv2109/uci-impl/internal/board"
+	. "github.com/mhv2109/uci-impl/internal/solver/utils"
+)
+
+var _ = Describe("Params", func() {
+	AfterEach(func() {
+		ResetParams()
+	})
+
+	It("Registers every parameter once, with its default in range", func() {
+		names := make(map[string]bool)
+		for _, param := range Params() {
+			name := strings.ToLower(param.Name)
+			Expect(names).
+				ToNot(HaveKey(name))
+			names[name] = true
+
+			Expect(param.Value()).
+				To(Equal(param.Default))
+			Expect(param.Default).
+				To(BeNumerically(">=", param.Min))
+			Expect(param.Default).
+				To(BeNumerically("<=", param.Max))
+		}
+	})
+
+	It("Looks up parameters ignoring case", func() {
+		Expect(GetParam("pawn value")).
+			To(Equal(GetParam("Pawn Value")))
+		Expect(GetParam("No Such Param")).
+			To(BeNil())
+	})
+
+	It("Clamps values to the range", func() {
+		param := GetParam("Queen Value")
+		param.Set(param.Max + 1)
+		Expect(param.Value()).
+			To(Equal(param.Max))
+	})
+
+	It("Changes the evaluation", func() {
+		GetParam("Knight Value").Set(350)
+		Expect(KnightValue).
+			To(BeEquivalentTo(350))
+
+		ResetParams()
+		Expect(KnightValue).
+			To(BeEquivalentTo(GetParam("Knight Value").Default))
+	})
+
+	It("Invalidates cached pawn structures", func() {
+		pos, _ := board.ParseFEN("4k3/8/8/8/8/2P5/2P5/4K3 w - - 0 1")
+		evaluator := NewEvaluator()
+		before := evaluator.Trace(pos)
+
+		GetParam("Doubled Pawn EG").Set(-100)
+		after := evaluator.Trace(pos)
+		Expect(after.Term(PawnsTerm, board.White).EG).
+			To(BeNumerically("<", before.Term(PawnsTerm, board.White).EG))
+	})
+})
+
+var _ = Describe("Param files", func() {
+	var defaults []int
+
+	BeforeEach(func() {
+		defaults = make([]int, 0, len(Params()))
+		for _, param := range Params() {
+			defaults = append(defaults, param.Default)
+		}
+	})
+
+	AfterEach(func() {
+		for i, param := range Params() {
+			param.Default = defaults[i]
+		}
+		ResetParams()
+	})
+
+	It("Loads the parameters written", func() {
+		GetParam("Rook Value").Set(525)
+		var buf bytes.Buffer
+		Expect(WriteParams(&buf)).
+			To(Succeed())
+
+		ResetParams()
+		Expect(LoadParams(&buf)).
+			To(Succeed())
+		Expect(RookValue).
+			To(BeEquivalentTo(525))
+	})
+
+	It("Makes loaded parameters the defaults", func() {
+		Expect(LoadParams(strings.NewReader("# tuned\n\nBishop Value = 330\n"))).
+			To(Succeed())
+		ResetParams()
+		Expect(GetParam("Bishop Value").Default).
+			To(Equal(330))
+		Expect(BishopValue).
+			To(BeEquivalentTo(330))
+	})
+
+	It("Rejects unknown parameters", func() {
+		Expect(LoadParams(strings.NewReader("No Such Param = 1\n"))).
+			ToNot(Succeed())
+	})
+})
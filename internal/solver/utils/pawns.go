@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+// Pawn structure weights.
+var (
+	doubledPawn  = Score{-10, -20}
+	isolatedPawn = Score{-10, -15}
+	backwardPawn = Score{-8, -10}
+
+	// passed pawn bonuses by rank, from the side's own point of view
+	passedPawnMG = [8]CentiPawns{0, 5, 10, 15, 25, 40, 60, 0}
+	passedPawnEG = [8]CentiPawns{0, 10, 20, 35, 60, 100, 150, 0}
+
+	// endgame bonuses per square of distance between the kings and the
+	// square in front of a passed pawn, scaled by the pawn's rank
+	passedPawnEnemyKing CentiPawns = 5
+	passedPawnOwnKing   CentiPawns = -2
+)
+
+const (
+	fileA board.Bitboard = 0x0101010101010101
+
+	pawnTableSize = 1 << 14
+)
+
+// pawnEntry caches the evaluation of a pawn structure, and the passed pawns
+// whose value depends on the kings as well.
+type pawnEntry struct {
+	key    uint64
+	score  [2]Score
+	passed [2]board.Bitboard
+}
+
+// pawnTable is a hash table of pawn structures, indexed by the pawn key of
+// board.Position.  Pawn structures change rarely during a search, so most
+// lookups hit.
+type pawnTable struct {
+	entries []pawnEntry
+}
+
+func newPawnTable() *pawnTable {
+	return &pawnTable{make([]pawnEntry, pawnTableSize)}
+}
+
+// Clear empties the table.
+func (table *pawnTable) Clear() {
+	for i := range table.entries {
+		table.entries[i] = pawnEntry{}
+	}
+}
+
+// Probe returns the entry of the pawns with the pawn key key, evaluating them
+// if they're not cached.  Empty entries match boards without pawns, whose key
+// is zero and which evaluate to zero.
+func (table *pawnTable) Probe(key uint64, pawns [2]board.Bitboard) *pawnEntry {
+	entry := &table.entries[key%pawnTableSize]
+	if entry.key != key {
+		*entry = evaluatePawns(pawns)
+		entry.key = key
+	}
+	return entry
+}
+
+// evaluatePawns evaluates the pawn structure of both sides, given as bitboards
+// indexed by color.
+func evaluatePawns(pawns [2]board.Bitboard) pawnEntry {
+	var entry pawnEntry
+	for _, us := range []board.Color{board.White, board.Black} {
+		own, enemy := pawns[us], pawns[us.Other()]
+		for bb := own; bb != 0; {
+			sq := bb.Pop()
+			sameFile, rank := fileA<<uint(sq.File()), relativeRank(us, sq)
+			adjacent := adjacentFiles(sq.File())
+
+			if own&forwardMask(us, sq)&sameFile != 0 {
+				// a pawn behind another is doubled
+				entry.score[us] = entry.score[us].Add(doubledPawn)
+			}
+
+			if own&adjacent == 0 {
+				entry.score[us] = entry.score[us].Add(isolatedPawn)
+			} else if own&adjacent&^forwardMask(us, sq) == 0 && stopAttacked(us, sq, enemy) {
+				// no pawn can come up to support it, and it can't advance
+				// safely
+				entry.score[us] = entry.score[us].Add(backwardPawn)
+			}
+
+			if enemy&forwardMask(us, sq)&(adjacent|sameFile) == 0 {
+				entry.passed[us] |= board.SquareBB(sq)
+				entry.score[us] = entry.score[us].Add(Score{passedPawnMG[rank], passedPawnEG[rank]})
+			}
+		}
+	}
+	return entry
+}
+
+// passedPawnKings returns the bonus of the passed pawns of color us in position
+// for the distances of the kings to their path.
+func passedPawnKings(position *board.Position, us board.Color, passed board.Bitboard) Score {
+	var s Score
+	own, enemy := position.King(us), position.King(us.Other())
+	for bb := passed; bb != 0; {
+		sq := bb.Pop()
+		weight := CentiPawns(relativeRank(us, sq) - 1)
+		if weight <= 0 {
+			continue
+		}
+		stop := sq + 8
+		if us == board.Black {
+			stop = sq - 8
+		}
+		s.EG += weight * (passedPawnEnemyKing*distance(enemy, stop) +
+			passedPawnOwnKing*distance(own, stop))
+	}
+	return s
+}
+
+// relativeRank returns the rank of sq, 0 to 7, from the point of view of color
+// us.
+func relativeRank(us board.Color, sq board.Square) int {
+	if us == board.Black {
+		return 7 - sq.Rank()
+	}
+	return sq.Rank()
+}
+
+func adjacentFiles(file int) board.Bitboard {
+	var mask board.Bitboard
+	if file > 0 {
+		mask |= fileA << uint(file-1)
+	}
+	if file < 7 {
+		mask |= fileA << uint(file+1)
+	}
+	return mask
+}
+
+// forwardMask returns all squares on ranks ahead of sq, from the point of view
+// of color us.
+func forwardMask(us board.Color, sq board.Square) board.Bitboard {
+	rank := uint(sq.Rank())
+	if us == board.Black {
+		return (1 << (8 * rank)) - 1
+	}
+	if rank == 7 {
+		return 0
+	}
+	return ^board.Bitboard(0) << (8 * (rank + 1))
+}
+
+// stopAttacked returns true if the square in front of the pawn of color us on
+// sq is attacked by an enemy pawn.
+func stopAttacked(us board.Color, sq board.Square, enemy board.Bitboard) bool {
+	if relativeRank(us, sq) == 7 {
+		return false
+	}
+	stop := sq + 8
+	if us == board.Black {
+		stop = sq - 8
+	}
+	// enemy pawns attack stop from the squares a pawn of ours on it attacks
+	return board.PawnAttacks(us, stop)&enemy != 0
+}
+
+func distance(a, b board.Square) CentiPawns {
+	df, dr := a.File()-b.File(), a.Rank()-b.Rank()
+	if df < 0 {
+		df = -df
+	}
+	if dr < 0 {
+		dr = -dr
+	}
+	if df > dr {
+		return CentiPawns(df)
+	}
+	return CentiPawns(dr)
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+// Weights of the piece-square tables, in percent, for tuning.
+var (
+	pawnSquares   = Score{100, 100}
+	knightSquares = Score{100, 100}
+	bishopSquares = Score{100, 100}
+	rookSquares   = Score{100, 100}
+	queenSquares  = Score{100, 100}
+	kingSquares   = Score{100, 100}
+)
+
+// Piece-square tables, in centipawns, from White's point of view and laid out
+// as the board is printed: a8 first, h1 last.  Black's pieces use the tables
+// mirrored vertically.
+var (
+	pawnTableMG = [nSquares]CentiPawns{
+		0, 0, 0, 0, 0, 0, 0, 0,
+		50, 50, 50, 50, 50, 50, 50, 50,
+		10, 10, 20, 30, 30, 20, 10, 10,
+		5, 5, 10, 25, 25, 10, 5, 5,
+		0, 0, 0, 20, 20, 0, 0, 0,
+		5, -5, -10, 0, 0, -10, -5, 5,
+		5, 10, 10, -20, -20, 10, 10, 5,
+		0, 0, 0, 0, 0, 0, 0, 0}
+
+	pawnTableEG = [nSquares]CentiPawns{
+		0, 0, 0, 0, 0, 0, 0, 0,
+		60, 60, 60, 60, 60, 60, 60, 60,
+		40, 40, 40, 40, 40, 40, 40, 40,
+		25, 25, 25, 25, 25, 25, 25, 25,
+		15, 15, 15, 15, 15, 15, 15, 15,
+		5, 5, 5, 5, 5, 5, 5, 5,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0}
+
+	knightTable = [nSquares]CentiPawns{
+		-50, -40, -30, -30, -30, -30, -40, -50,
+		-40, -20, 0, 0, 0, 0, -20, -40,
+		-30, 0, 10, 15, 15, 10, 0, -30,
+		-30, 5, 15, 20, 20, 15, 5, -30,
+		-30, 0, 15, 20, 20, 15, 0, -30,
+		-30, 5, 10, 15, 15, 10, 5, -30,
+		-40, -20, 0, 5, 5, 0, -20, -40,
+		-50, -40, -30, -30, -30, -30, -40, -50}
+
+	bishopTable = [nSquares]CentiPawns{
+		-20, -10, -10, -10, -10, -10, -10, -20,
+		-10, 0, 0, 0, 0, 0, 0, -10,
+		-10, 0, 5, 10, 10, 5, 0, -10,
+		-10, 5, 5, 10, 10, 5, 5, -10,
+		-10, 0, 10, 10, 10, 10, 0, -10,
+		-10, 10, 10, 10, 10, 10, 10, -10,
+		-10, 5, 0, 0, 0, 0, 5, -10,
+		-20, -10, -10, -10, -10, -10, -10, -20}
+
+	rookTable = [nSquares]CentiPawns{
+		0, 0, 0, 0, 0, 0, 0, 0,
+		5, 10, 10, 10, 10, 10, 10, 5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		0, 0, 0, 5, 5, 0, 0, 0}
+
+	queenTable = [nSquares]CentiPawns{
+		-20, -10, -10, -5, -5, -10, -10, -20,
+		-10, 0, 0, 0, 0, 0, 0, -10,
+		-10, 0, 5, 5, 5, 5, 0, -10,
+		-5, 0, 5, 5, 5, 5, 0, -5,
+		0, 0, 5, 5, 5, 5, 0, -5,
+		-10, 5, 5, 5, 5, 5, 0, -10,
+		-10, 0, 5, 0, 0, 0, 0, -10,
+		-20, -10, -10, -5, -5, -10, -10, -20}
+
+	kingTableMG = [nSquares]CentiPawns{
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-20, -30, -30, -40, -40, -30, -30, -20,
+		-10, -20, -20, -20, -20, -20, -20, -10,
+		20, 20, 0, 0, 0, 0, 20, 20,
+		20, 30, 10, 0, 0, 10, 30, 20}
+
+	kingTableEG = [nSquares]CentiPawns{
+		-50, -40, -30, -20, -20, -30, -40, -50,
+		-30, -20, -10, 0, 0, -10, -20, -30,
+		-30, -10, 20, 30, 30, 20, -10, -30,
+		-30, -10, 30, 40, 40, 30, -10, -30,
+		-30, -10, 30, 40, 40, 30, -10, -30,
+		-30, -10, 20, 30, 30, 20, -10, -30,
+		-30, -30, 0, 0, 0, 0, -30, -30,
+		-50, -30, -30, -30, -30, -30, -30, -50}
+)
+
+// PieceSquareScore returns the value of piece on sq, the sum of its material
+// value, except for kings, and its piece-square table bonus.
+func PieceSquareScore(piece board.Piece, sq board.Square) Score {
+	s := pieceSquare(piece, sq)
+	if piece.Type() != board.King {
+		value := pieceValue(piece.Type())
+		s = s.Add(Score{value, value})
+	}
+	return s
+}
+
+// pieceSquare returns the piece-square table bonus of piece on sq.
+func pieceSquare(piece board.Piece, sq board.Square) Score {
+	// the tables start at a8, squares at a1
+	i := int(sq)
+	if piece.Color() == board.White {
+		i = (7-sq.Rank())*8 + sq.File()
+	}
+
+	switch piece.Type() {
+	case board.Pawn:
+		return weighSquare(pawnSquares, pawnTableMG[i], pawnTableEG[i])
+	case board.Knight:
+		return weighSquare(knightSquares, knightTable[i], knightTable[i])
+	case board.Bishop:
+		return weighSquare(bishopSquares, bishopTable[i], bishopTable[i])
+	case board.Rook:
+		return weighSquare(rookSquares, rookTable[i], rookTable[i])
+	case board.Queen:
+		return weighSquare(queenSquares, queenTable[i], queenTable[i])
+	case board.King:
+		return weighSquare(kingSquares, kingTableMG[i], kingTableEG[i])
+	}
+	return Score{}
+}
+
+func weighSquare(weight Score, mg, eg CentiPawns) Score {
+	return Score{mg * weight.MG / 100, eg * weight.EG / 100}
+}
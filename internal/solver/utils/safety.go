@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"github.com/mhv2109/uci-impl/internal/board"
+)
+
+// King safety weights.
+var (
+	kingShelterPawn  = Score{12, 0}  // per own pawn in front of the king
+	kingSemiOpenFile = Score{-15, 0} // per file next to the king without own pawns
+	kingOpenFile     = Score{-10, 0} // extra if the file has no enemy pawns either
+
+	// per square of the king zone attacked, by attacker type, if at least two
+	// pieces attack the zone
+	kingAttackKnight = Score{-8, 0}
+	kingAttackBishop = Score{-8, 0}
+	kingAttackRook   = Score{-10, 0}
+	kingAttackQueen  = Score{-14, 0}
+)
+
+// Mobility weights, per square a piece can move to beyond the typical number of
+// squares.
+var (
+	knightMobility = Score{4, 4}
+	bishopMobility = Score{5, 5}
+	rookMobility   = Score{2, 4}
+	queenMobility  = Score{1, 2}
+)
+
+// typical number of squares a piece can move to, which scores no mobility
+// bonus
+const (
+	knightTypicalMobility = 4
+	bishopTypicalMobility = 6
+	rookTypicalMobility   = 6
+	queenTypicalMobility  = 12
+)
+
+// kingAttackers are the types of the pieces counted as attacking the king
+// zone.  The king counts towards the number of attackers, but scores nothing
+// itself.
+var kingAttackers = []board.PieceType{board.Knight, board.Bishop, board.Rook, board.Queen, board.King}
+
+// kingSafety evaluates the pawn shelter and the open files around the king of
+// color us in position, and the enemy pieces attacking the squares around it.
+func kingSafety(position *board.Position, us board.Color) Score {
+	var s Score
+	them := us.Other()
+	own, enemy := position.Pieces(us, board.Pawn), position.Pieces(them, board.Pawn)
+
+	king := position.King(us)
+	file := king.File()
+	for f := file - 1; f <= file+1; f++ {
+		if f < 0 || f > 7 {
+			continue
+		}
+		mask := fileA << uint(f)
+		if own&mask == 0 {
+			s = s.Add(kingSemiOpenFile)
+			if enemy&mask == 0 {
+				s = s.Add(kingOpenFile)
+			}
+		}
+	}
+
+	// the two ranks in front of the king
+	shelter := board.KingAttacks(king) & forwardMask(us, king)
+	if us == board.Black {
+		shelter |= shelter >> 8
+	} else {
+		shelter |= shelter << 8
+	}
+	s = s.Add(scale(kingShelterPawn, (shelter & own).Count()))
+
+	zone := board.KingAttacks(king) | board.SquareBB(king)
+	occupied := position.AllOccupied()
+	var attackers int
+	var attack Score
+	for _, t := range kingAttackers {
+		for pieces := position.Pieces(them, t); pieces != 0; {
+			attacked := (pieceAttacks(t, pieces.Pop(), occupied) & zone).Count()
+			if attacked == 0 {
+				continue
+			}
+			attackers++
+			attack = attack.Add(scale(kingAttackWeight(t), attacked))
+		}
+	}
+	if attackers >= 2 {
+		s = s.Add(attack)
+	}
+
+	return s
+}
+
+// kingAttackWeight returns the weight per square of the king zone a piece of
+// type t attacks.
+func kingAttackWeight(t board.PieceType) Score {
+	switch t {
+	case board.Knight:
+		return kingAttackKnight
+	case board.Bishop:
+		return kingAttackBishop
+	case board.Rook:
+		return kingAttackRook
+	case board.Queen:
+		return kingAttackQueen
+	}
+	return Score{}
+}
+
+// mobilePieces are the types of the pieces whose mobility is evaluated.
+var mobilePieces = []board.PieceType{board.Knight, board.Bishop, board.Rook, board.Queen}
+
+// mobility evaluates the number of squares the pieces of color us in position
+// can move to, not counting squares occupied by their own pieces or attacked
+// by enemy pawns.
+func mobility(position *board.Position, us board.Color) Score {
+	var s Score
+	them := us.Other()
+	available := ^position.Occupied(us) &^ pawnAttackMap(them, position.Pieces(them, board.Pawn))
+	occupied := position.AllOccupied()
+	for _, t := range mobilePieces {
+		weight, typical := mobilityWeight(t)
+		for pieces := position.Pieces(us, t); pieces != 0; {
+			moves := (pieceAttacks(t, pieces.Pop(), occupied) & available).Count()
+			s = s.Add(scale(weight, moves-typical))
+		}
+	}
+	return s
+}
+
+// mobilityWeight returns the weight per square a piece of type t can move to,
+// and the typical number of squares, which scores nothing.
+func mobilityWeight(t board.PieceType) (Score, int) {
+	switch t {
+	case board.Knight:
+		return knightMobility, knightTypicalMobility
+	case board.Bishop:
+		return bishopMobility, bishopTypicalMobility
+	case board.Rook:
+		return rookMobility, rookTypicalMobility
+	case board.Queen:
+		return queenMobility, queenTypicalMobility
+	}
+	return Score{}, 0
+}
+
+func scale(s Score, n int) Score {
+	return Score{s.MG * CentiPawns(n), s.EG * CentiPawns(n)}
+}
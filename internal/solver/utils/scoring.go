@@ -2,23 +2,78 @@ package utils
 
 import (
 	"github.com/notnil/chess"
+
+	"github.com/mhv2109/uci-impl/internal/board"
 )
 
 type CentiPawns int
 
 const nSquares = 64
 
-const (
+// Piece values, which are tunable evaluation parameters, except for the king.
+var (
 	PawnValue   CentiPawns = 100
 	KnightValue CentiPawns = 300
 	BishopValue CentiPawns = 300
 	RookValue   CentiPawns = 500
 	QueenValue  CentiPawns = 900
-	KingValue   CentiPawns = 1000000
 )
 
+const KingValue CentiPawns = 1000000
+
 var MaxScore CentiPawns = 1003900
 
+// maxMatePly is the longest distance to mate, in plies, that mate scores can
+// represent.  Scores within maxMatePly of MaxScore are mate scores.
+const maxMatePly = 1000
+
+// MateIn returns the score of delivering checkmate ply plies from the root, so
+// shorter mates score higher.
+func MateIn(ply int) CentiPawns {
+	return MaxScore - CentiPawns(ply)
+}
+
+// MatedIn returns the score of being checkmated ply plies from the root.
+func MatedIn(ply int) CentiPawns {
+	return -MateIn(ply)
+}
+
+// IsMate returns true if score is a mate score.
+func IsMate(score CentiPawns) bool {
+	return score > MaxScore-maxMatePly || score < -MaxScore+maxMatePly
+}
+
+// MateDistance returns the number of moves, not plies, until checkmate for a
+// mate score, negative if the engine is getting mated.
+func MateDistance(score CentiPawns) int {
+	if score > 0 {
+		return int(MaxScore-score+1) / 2
+	}
+	return -int(MaxScore+score+1) / 2
+}
+
+// MateToTT converts a mate score relative to the root to one relative to the
+// node at ply, so it can be stored in a transposition table and reused at any
+// ply.  Other scores are returned unchanged.
+func MateToTT(score CentiPawns, ply int) CentiPawns {
+	if !IsMate(score) {
+		return score
+	} else if score > 0 {
+		return score + CentiPawns(ply)
+	}
+	return score - CentiPawns(ply)
+}
+
+// MateFromTT is the inverse of MateToTT.
+func MateFromTT(score CentiPawns, ply int) CentiPawns {
+	if !IsMate(score) {
+		return score
+	} else if score > 0 {
+		return score - CentiPawns(ply)
+	}
+	return score + CentiPawns(ply)
+}
+
 func BlackAdvantage(board *chess.Board) CentiPawns {
 	white, black := getAllPiecesByColor(board)
 	return score(black) - score(white)
@@ -37,20 +92,26 @@ func score(s []chess.Piece) CentiPawns {
 	return score
 }
 
+// scorePiece returns the value of piece of the chess package, whose piece types
+// the board package numbers the same.
 func scorePiece(piece chess.Piece) CentiPawns {
+	return pieceValue(board.PieceType(piece.Type()))
+}
+
+func pieceValue(t board.PieceType) CentiPawns {
 	var ret CentiPawns
-	switch piece.Type() {
-	case chess.Pawn:
+	switch t {
+	case board.Pawn:
 		ret = PawnValue
-	case chess.Knight:
+	case board.Knight:
 		ret = KnightValue
-	case chess.Bishop:
+	case board.Bishop:
 		ret = BishopValue
-	case chess.Rook:
+	case board.Rook:
 		ret = RookValue
-	case chess.Queen:
+	case board.Queen:
 		ret = QueenValue
-	case chess.King:
+	case board.King:
 		ret = KingValue
 	default:
 		panic("Invalid type")
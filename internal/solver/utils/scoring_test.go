@@ -33,3 +33,45 @@ var _ = Describe("Score", func() {
 			To(Equal(expected))
 	})
 })
+
+var _ = Describe("Mate scores", func() {
+	It("Shorter mates score higher", func() {
+		Expect(MateIn(1)).
+			To(BeNumerically(">", MateIn(3)))
+		Expect(MatedIn(1)).
+			To(BeNumerically("<", MatedIn(3)))
+	})
+
+	It("Are recognized", func() {
+		Expect(IsMate(MateIn(5))).
+			To(BeTrue())
+		Expect(IsMate(MatedIn(5))).
+			To(BeTrue())
+		Expect(IsMate(QueenValue)).
+			To(BeFalse())
+	})
+
+	It("Convert plies to moves", func() {
+		Expect(MateDistance(MateIn(1))).
+			To(Equal(1))
+		Expect(MateDistance(MateIn(3))).
+			To(Equal(2))
+		Expect(MateDistance(MatedIn(2))).
+			To(Equal(-1))
+		Expect(MateDistance(MatedIn(4))).
+			To(Equal(-2))
+	})
+
+	It("Are stored relative to the node", func() {
+		score := MateIn(7)
+		stored := MateToTT(score, 4)
+		Expect(stored).
+			To(Equal(MateIn(3)))
+		Expect(MateFromTT(stored, 2)).
+			To(Equal(MateIn(5)))
+		Expect(MateFromTT(MateToTT(MatedIn(6), 2), 2)).
+			To(Equal(MatedIn(6)))
+		Expect(MateToTT(PawnValue, 4)).
+			To(Equal(PawnValue))
+	})
+})